@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/oscarpfernandez/immudbcc/pkg/api"
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
 	"github.com/oscarpfernandez/immudbcc/pkg/server"
 )
 
@@ -19,14 +26,33 @@ func main() {
 	fsWrite := flag.NewFlagSet("write", flag.ContinueOnError)
 	inJSONPath := fsWrite.String("input-json", "", "JSON path of the file to store")
 	numWorkers := fsWrite.Int("workers", 50, "number of workers")
+	atomicWrite := fsWrite.Bool("atomic", false, "write the document in a single batch")
+	exactNumbers := fsWrite.Bool("exact-numbers", false, "store numbers as their exact decimal text")
+	orderedKeys := fsWrite.Bool("ordered-keys", false, "preserve the order of object members")
+	saltedProperties := fsWrite.Bool("salted", false, "prefix the value of each property with a random salt")
+	streamWrite := fsWrite.Bool("stream", false, "flatten the JSON document as it is read")
+	writeFormat := fsWrite.String("format", "json", "format of the file to store: json, yaml, cbor or msgpack")
 	writeDocID := fsWrite.String("doc-id", "", "document ID")
 
 	fsRead := flag.NewFlagSet("read", flag.ContinueOnError)
 	outJSONPath := fsRead.String("output-json", "", "JSON path of the file to read")
+	readFormat := fsRead.String("format", "json", "format of the file to read: json, yaml, cbor or msgpack")
 	readDocID := fsRead.String("doc-id", "", "document ID")
 
+	fsExportBundle := flag.NewFlagSet("export-bundle", flag.ContinueOnError)
+	exportBundlePath := fsExportBundle.String("bundle", "", "path of the proof bundle file to write")
+	exportDocID := fsExportBundle.String("doc-id", "", "document ID")
+
+	fsVerifyBundle := flag.NewFlagSet("verify-bundle", flag.ContinueOnError)
+	verifyBundlePath := fsVerifyBundle.String("bundle", "", "path of the proof bundle file to verify")
+	verifyHash := fsVerifyBundle.String("hash", "", "expected hash of the document, if known")
+	verifyServerKey := fsVerifyBundle.String("server-key", "", "path of the PEM public key the database signs its roots with")
+	verifyManifestKeys := keyFlag{}
+	fsVerifyBundle.Var(verifyManifestKeys, "manifest-key", "keyID=path of a PEM public key the manifest may be signed by, repeatable")
+	verifyOutJSONPath := fsVerifyBundle.String("output-json", "", "JSON path of the verified document to write, if any")
+
 	if len(os.Args) <= 1 {
-		fmt.Printf(os.Args[0] + " <read | write>  [flags]\n")
+		fmt.Printf(os.Args[0] + " <read | write | export-bundle | verify-bundle>  [flags]\n")
 		fmt.Println("* Flags <write>")
 		flag.PrintDefaults()
 		os.Exit(1)
@@ -37,11 +63,25 @@ func main() {
 		_ = fsWrite.Parse(os.Args[2:])
 	case "read":
 		_ = fsRead.Parse(os.Args[2:])
+	case "export-bundle":
+		_ = fsExportBundle.Parse(os.Args[2:])
+	case "verify-bundle":
+		_ = fsVerifyBundle.Parse(os.Args[2:])
 	default:
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
+	// Proof bundles are verified without a database.
+	if os.Args[1] == "verify-bundle" && fsVerifyBundle.Parsed() {
+		if *verifyBundlePath == "" || (*verifyServerKey == "" && *verifyHash == "") {
+			fsVerifyBundle.PrintDefaults()
+			os.Exit(1)
+		}
+		verifyBundle(*verifyBundlePath, *verifyServerKey, verifyManifestKeys, *verifyHash, *verifyOutJSONPath)
+		return
+	}
+
 	dbServer, err := server.New(server.Config{AuthEnabled: false, LogFile: "immuserver.log"})
 	if err != nil {
 		log.Fatalf("Failed to init server: %v", err)
@@ -61,7 +101,7 @@ func main() {
 			fsWrite.PrintDefaults()
 			os.Exit(1)
 		} else {
-			writeDocumentToDB(*numWorkers, *writeDocID, *inJSONPath)
+			writeDocumentToDB(*numWorkers, *atomicWrite, *exactNumbers, *orderedKeys, *saltedProperties, *streamWrite, *writeFormat, *writeDocID, *inJSONPath)
 		}
 	}
 
@@ -70,12 +110,32 @@ func main() {
 			fsWrite.PrintDefaults()
 			os.Exit(1)
 		} else {
-			readDocumentFromDB(*numWorkers, *readDocID, *outJSONPath)
+			readDocumentFromDB(*numWorkers, *readFormat, *readDocID, *outJSONPath)
+		}
+	}
+
+	if os.Args[1] == "export-bundle" && fsExportBundle.Parsed() {
+		if *exportBundlePath == "" || *exportDocID == "" {
+			fsExportBundle.PrintDefaults()
+			os.Exit(1)
+		} else {
+			exportBundle(*exportDocID, *exportBundlePath)
 		}
 	}
 }
 
-func writeDocumentToDB(numWorkers int, docID, jsonPath string) {
+func writeDocumentToDB(numWorkers int, atomicWrite, exactNumbers, orderedKeys, saltedProperties, stream bool, format, docID, jsonPath string) {
+	codec, err := doc.CodecByName(format)
+	if err != nil {
+		log.Fatalf("Invalid format: %v", err)
+	}
+	if stream && codec != doc.JSONCodec {
+		log.Fatalf("Only JSON documents can be streamed")
+	}
+	if stream && atomicWrite {
+		log.Fatalf("Streamed documents can not be written atomically")
+	}
+
 	if _, err := os.Stat(jsonPath); os.IsExist(err) {
 		log.Fatalf("File does not exist: %s", err)
 	}
@@ -86,14 +146,23 @@ func writeDocumentToDB(numWorkers int, docID, jsonPath string) {
 	}
 	defer jsonReader.Close()
 
-	conf := api.DefaultConfig().WithNumberWorkers(numWorkers)
+	conf := api.DefaultConfig().WithNumberWorkers(numWorkers).WithExactNumbers(exactNumbers).
+		WithOrderedKeys(orderedKeys).WithSaltedProperties(saltedProperties)
+	if atomicWrite {
+		conf = conf.WithWriteMode(api.WriteModeAtomic)
+	}
 	apiManager, err := api.New(conf)
 	if err != nil {
 		log.Fatalf("Failed to start API manager: %v", err)
 	}
 
 	now := time.Now()
-	result, err := apiManager.StoreDocument(context.Background(), docID, jsonReader)
+	var result *api.StoreDocumentResult
+	if stream {
+		result, err = apiManager.StoreDocumentStream(context.Background(), docID, jsonReader)
+	} else {
+		result, err = apiManager.StoreDocumentWithCodec(context.Background(), docID, jsonReader, codec)
+	}
 	if err != nil {
 		log.Fatalf("Failed to store document: %v", err)
 	}
@@ -102,7 +171,12 @@ func writeDocumentToDB(numWorkers int, docID, jsonPath string) {
 	log.Printf("Result hash: Index(%d), Hash(%s)", result.Index, result.Hash)
 }
 
-func readDocumentFromDB(numWorkers int, docID, jsonPath string) {
+func readDocumentFromDB(numWorkers int, format, docID, jsonPath string) {
+	codec, err := doc.CodecByName(format)
+	if err != nil {
+		log.Fatalf("Invalid format: %v", err)
+	}
+
 	jsonWriter, err := openWriteFile(jsonPath)
 	if err != nil {
 		log.Fatalf("Failed to open file: %v", err)
@@ -116,7 +190,7 @@ func readDocumentFromDB(numWorkers int, docID, jsonPath string) {
 	}
 
 	now := time.Now()
-	result, err := apiManager.GetDocument(context.Background(), docID)
+	result, err := apiManager.GetDocumentWithCodec(context.Background(), docID, codec)
 	if err != nil {
 		log.Fatalf("Failed to store document: %v", err)
 	}
@@ -130,6 +204,123 @@ func readDocumentFromDB(numWorkers int, docID, jsonPath string) {
 	log.Printf("Read document execution time: %s", execTime)
 }
 
+func exportBundle(docID, bundlePath string) {
+	bundleWriter, err := openWriteFile(bundlePath)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	defer bundleWriter.Close()
+
+	apiManager, err := api.New(api.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to start API manager: %v", err)
+	}
+
+	bundle, err := apiManager.ExportProofBundle(context.Background(), docID)
+	if err != nil {
+		log.Fatalf("Failed to export proof bundle: %v", err)
+	}
+
+	log.Printf("Writing proof bundle file: %s", bundlePath)
+	if _, err := bundle.WriteTo(bundleWriter); err != nil {
+		log.Fatalf("Failed to write proof bundle file: %v", err)
+	}
+}
+
+func verifyBundle(bundlePath, serverKeyPath string, manifestKeys keyFlag, hash, jsonPath string) {
+	trust := api.BundleTrust{Hash: hash}
+	if serverKeyPath != "" {
+		serverKey, err := readPublicKey(serverKeyPath)
+		if err != nil {
+			log.Fatalf("Failed to read server key: %v", err)
+		}
+		ecKey, ok := serverKey.(*ecdsa.PublicKey)
+		if !ok {
+			log.Fatalf("Failed to read server key: unsupported key type %T", serverKey)
+		}
+		trust.ServerKey = ecKey
+	}
+	if len(manifestKeys) > 0 {
+		trust.TrustedKeys = map[string]crypto.PublicKey{}
+		for keyID, path := range manifestKeys {
+			key, err := readPublicKey(path)
+			if err != nil {
+				log.Fatalf("Failed to read manifest key '%s': %v", keyID, err)
+			}
+			trust.TrustedKeys[keyID] = key
+		}
+	}
+
+	bundleReader, err := openReadFile(bundlePath)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	defer bundleReader.Close()
+
+	bundle, err := api.ReadProofBundle(bundleReader)
+	if err != nil {
+		log.Fatalf("Failed to read proof bundle: %v", err)
+	}
+
+	manifest, err := bundle.Verify(trust)
+	if err != nil {
+		log.Fatalf("Proof bundle failed verification: %v", err)
+	}
+	log.Printf("Proof bundle verified: DocumentID(%s), Root(%d, %x), Hash(%s)",
+		bundle.DocumentID, bundle.Root.GetIndex(), bundle.Root.GetRoot(), manifest.Hash)
+
+	if jsonPath == "" {
+		return
+	}
+	payload, err := bundle.Payload()
+	if err != nil {
+		log.Fatalf("Failed to encode document: %v", err)
+	}
+	jsonWriter, err := openWriteFile(jsonPath)
+	if err != nil {
+		log.Fatalf("Failed to open file: %v", err)
+	}
+	defer jsonWriter.Close()
+
+	log.Printf("Writing JSON file: %s", jsonPath)
+	if _, err := jsonWriter.Write(payload); err != nil {
+		log.Fatalf("Failed to write JSON file: %v", err)
+	}
+}
+
+// keyFlag collects repeated keyID=path flags.
+type keyFlag map[string]string
+
+func (k keyFlag) String() string {
+	var pairs []string
+	for keyID, path := range k {
+		pairs = append(pairs, keyID+"="+path)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (k keyFlag) Set(value string) error {
+	idx := strings.Index(value, "=")
+	if idx <= 0 {
+		return fmt.Errorf("invalid key '%s', expected keyID=path", value)
+	}
+	k[value[:idx]] = value[idx+1:]
+	return nil
+}
+
+// readPublicKey reads a PEM encoded PKIX public key.
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
 func openReadFile(path string) (io.ReadCloser, error) {
 	return os.Open(path)
 }
@@ -4,6 +4,9 @@ go 1.15
 
 require (
 	github.com/codenotary/immudb v0.8.1
+	github.com/codenotary/merkletree v0.1.2-0.20200720105344-68d95395a656
+	github.com/golang/protobuf v1.4.0
 	github.com/stretchr/testify v1.5.1
 	google.golang.org/grpc v1.29.1
+	gopkg.in/yaml.v2 v2.2.5
 )
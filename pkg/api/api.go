@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,22 +17,46 @@ import (
 	"github.com/oscarpfernandez/immudbcc/pkg/worker"
 
 	immuclient "github.com/codenotary/immudb/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	defaultNumWorkers = 50
 )
 
+// WriteMode defines how the properties of a document are written in the Database.
+type WriteMode int
+
+const (
+	// WriteModeConcurrent writes each property with its own SafeSet through a
+	// pool of workers, and only then writes the document manifest.
+	WriteModeConcurrent WriteMode = iota
+	// WriteModeAtomic writes every property and the document manifest in a
+	// single batch, committed as an all-or-nothing operation.
+	WriteModeAtomic
+)
+
 // Config represents the required API options.
 type Config struct {
-	NumberWorkers int
-	ClientOptions *immuclient.Options
+	NumberWorkers    int
+	WriteMode        WriteMode
+	ExactNumbers     bool                        // Store numbers as their exact decimal text, rather than as float64.
+	OrderedKeys      bool                        // Preserve the order of object members, rather than sorting them.
+	SaltedProperties bool                        // Prefix the value of each property with a random salt.
+	ChunkSize        int                         // Size of the chunks of attachments, in bytes.
+	Signer           crypto.Signer               // Signs the manifests written, if set.
+	SignerKeyID      string                      // Key ID recorded in the manifests signed by Signer.
+	TrustedKeys      map[string]crypto.PublicKey // Keys the manifests read must be signed by, if any, indexed by key ID.
+	ClientOptions    *immuclient.Options
 }
 
 // DefaultConfig defines a configuration with stock options.
 func DefaultConfig() *Config {
 	return &Config{
 		NumberWorkers: defaultNumWorkers,
+		WriteMode:     WriteModeConcurrent,
+		ChunkSize:     doc.DefaultChunkSize,
 		ClientOptions: immuclient.DefaultOptions().WithAuth(false),
 	}
 }
@@ -40,6 +67,57 @@ func (c *Config) WithNumberWorkers(numWorkers int) *Config {
 	return c
 }
 
+// WithWriteMode set the write mode used on Store actions.
+func (c *Config) WithWriteMode(mode WriteMode) *Config {
+	c.WriteMode = mode
+	return c
+}
+
+// WithExactNumbers set whether numbers are stored as their exact decimal text,
+// rather than as float64.
+func (c *Config) WithExactNumbers(exactNumbers bool) *Config {
+	c.ExactNumbers = exactNumbers
+	return c
+}
+
+// WithOrderedKeys set whether the order of object members is preserved, rather
+// than being sorted when reading documents.
+func (c *Config) WithOrderedKeys(orderedKeys bool) *Config {
+	c.OrderedKeys = orderedKeys
+	return c
+}
+
+// WithSaltedProperties set whether the value of each property written is
+// prefixed with a random salt, so that its digest cannot be brute-forced.
+func (c *Config) WithSaltedProperties(saltedProperties bool) *Config {
+	c.SaltedProperties = saltedProperties
+	return c
+}
+
+// WithChunkSize set the size of the chunks of attachments.
+func (c *Config) WithChunkSize(chunkSize int) *Config {
+	c.ChunkSize = chunkSize
+	return c
+}
+
+// WithSigner set the signer of the manifests written, either an ed25519 or an
+// ECDSA P-256 private key, and the key ID recorded in the signed manifests.
+func (c *Config) WithSigner(keyID string, signer crypto.Signer) *Config {
+	c.SignerKeyID = keyID
+	c.Signer = signer
+	return c
+}
+
+// WithTrustedKey add a key, either an ed25519.PublicKey or an *ecdsa.PublicKey
+// on the P-256 curve, to the keys the manifests read must be signed by.
+func (c *Config) WithTrustedKey(keyID string, key crypto.PublicKey) *Config {
+	if c.TrustedKeys == nil {
+		c.TrustedKeys = map[string]crypto.PublicKey{}
+	}
+	c.TrustedKeys[keyID] = key
+	return c
+}
+
 // WithClientOptions set the client options used to initialize the ImmuDB client.
 func (c *Config) WithClientOptions(options *immuclient.Options) *Config {
 	c.ClientOptions = options
@@ -49,31 +127,142 @@ func (c *Config) WithClientOptions(options *immuclient.Options) *Config {
 // ObjectManifest defines the top level object that describes a document in the
 // Database, including the object ID of said document, the indexes of each of its
 // properties and the global hash of the document (comprised by the hash of hashes,
-// sorted according to the associated property index).
+// sorted according to the associated property index). It also records the root
+// of the Merkle tree over the hashes of the properties' keys and values, sorted
+// by index too, which allows a single property to be proven to belong to the
+// document.
+//
+// Manifests written in a batch cannot know in advance the indexes assigned to
+// the properties written in that same batch. Instead, they record for each of
+// those properties the offset between the manifest's own index and the property
+// index, and the indexes and the global hash are resolved once the manifest is
+// read. Properties committed before the batch keep their absolute indexes. As
+// offsets are relative to the manifest, they record the hash of the keys and
+// values of their properties instead of the global hash, which the properties
+// found at the resolved indexes must match. Their Merkle root does not depend
+// on indexes either, hence it is recorded.
+//
+// Manifests of streamed documents do not record the indexes of all their
+// properties, which are instead written out in a chain of index blocks as the
+// properties are written. Such manifests record the index of the last block,
+// and the indexes of the properties written after it.
+//
+// Every manifest records the index of the previous manifest of the document, if
+// any, which for updates is the one the update is based on, so that an earlier
+// signed manifest cannot be replayed as the latest one.
+// Deleting a document writes a tombstone manifest, without properties, which
+// records the index and hash of the version it retires.
+//
+// Manifests may be signed, the signature covering the manifest as stored,
+// without the signature itself, and recording the ID of the signing key.
 type ObjectManifest struct {
-	ObjectID string   `json:"id"`
+	ObjectID      string   `json:"id"`
+	Indexes       []uint64 `json:"indexes"`
+	Offsets       []uint64 `json:"offsets,omitempty"`
+	IndexBlock    uint64   `json:"indexBlock,omitempty"`
+	Hash          string   `json:"hash"`
+	ContentHash   string   `json:"contentHash,omitempty"`
+	MerkleRoot    string   `json:"merkleRoot,omitempty"`
+	Deleted       bool     `json:"deleted,omitempty"`
+	PreviousIndex uint64   `json:"previousIndex,omitempty"`
+	PreviousHash  string   `json:"previousHash,omitempty"`
+	KeyID         string   `json:"keyID,omitempty"`
+	Signature     []byte   `json:"signature,omitempty"`
+}
+
+// resolveIndexes computes the absolute property indexes of a manifest written
+// in a batch, provided the index at which the manifest itself was stored. The
+// resolved indexes are appended to the manifest's indexes, and its offsets
+// are cleared.
+func (om *ObjectManifest) resolveIndexes(manifestIndex uint64) error {
+	for _, offset := range om.Offsets {
+		if offset == 0 || offset > manifestIndex {
+			return fmt.Errorf("manifest of object '%s' has invalid offset %d", om.ObjectID, offset)
+		}
+		om.Indexes = append(om.Indexes, manifestIndex-offset)
+	}
+	om.Offsets = nil
+
+	return nil
+}
+
+// indexBlock represents a block of the property indexes of a streamed document,
+// written along with its properties. Blocks are chained, each one recording the
+// index of the previous one, if any.
+type indexBlock struct {
 	Indexes  []uint64 `json:"indexes"`
-	Hash     string   `json:"hash"`
+	Previous uint64   `json:"previous,omitempty"`
+}
+
+// resolveIndexBlocks prepends to the indexes of a manifest the indexes recorded
+// by its chain of index blocks, read by the given function from their index,
+// and clears its last block. It returns the indexes of the blocks, from the
+// last one down.
+func (om *ObjectManifest) resolveIndexBlocks(manifestIndex uint64, readBlock func(index uint64) (key, value []byte, err error)) ([]uint64, error) {
+	var blockIndexes []uint64
+	var blocks [][]uint64
+	// Blocks are written before the manifest, each one after the previous one.
+	for blockIndex, bound := om.IndexBlock, manifestIndex; blockIndex != 0; {
+		if blockIndex >= bound {
+			return nil, fmt.Errorf("manifest of object '%s' has invalid index block %d", om.ObjectID, blockIndex)
+		}
+		key, value, err := readBlock(blockIndex)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(key, indexBlockKey(om.ObjectID)) {
+			return nil, fmt.Errorf("entry at index %d is not an index block of document ID '%s'", blockIndex, om.ObjectID)
+		}
+		block := &indexBlock{}
+		if err := json.Unmarshal(value, block); err != nil {
+			return nil, fmt.Errorf("unable to unmarshall index block at index %d: %v", blockIndex, err)
+		}
+		blockIndexes = append(blockIndexes, blockIndex)
+		blocks = append(blocks, block.Indexes)
+		blockIndex, bound = block.Previous, blockIndex
+	}
+
+	var indexes []uint64
+	for i := len(blocks) - 1; i >= 0; i-- {
+		indexes = append(indexes, blocks[i]...)
+	}
+	om.Indexes = append(indexes, om.Indexes...)
+	om.IndexBlock = 0
+
+	return blockIndexes, nil
+}
+
+// NotFoundError is returned when a document does not exist, or was deleted.
+type NotFoundError struct {
+	DocID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("document docID=%s not found", e.DocID)
 }
 
 // StoreDocumentResult represents the insertion result of a document.
 type StoreDocumentResult struct {
-	Index uint64
-	Hash  string
+	Index      uint64
+	Hash       string
+	MerkleRoot string
 }
 
 // GetDocumentResult represents the result of fetching a document.
 type GetDocumentResult struct {
-	ID      string
-	Index   uint64
-	Payload []byte
-	Hash    string
+	ID         string
+	Index      uint64
+	Payload    []byte
+	Hash       string
+	MerkleRoot string
 }
 
 // Manager represents the object required to use the API.
 type Manager struct {
-	conf   Config
-	client immuclient.ImmuClient
+	conf         Config
+	client       immuclient.ImmuClient
+	locks        documentLocks
+	verifiedRoot verifiedRoot
 }
 
 // New creates a new API manager object.
@@ -95,13 +284,37 @@ func New(c *Config) (*Manager, error) {
 // into key-value properties, representing the transversal property paths of the
 // original object.
 func (m *Manager) StoreDocument(ctx context.Context, docID string, r io.Reader) (*StoreDocumentResult, error) {
-	entryList, err := doc.RawToPropertyList(docID, r)
+	return m.StoreDocumentWithCodec(ctx, docID, r, doc.JSONCodec)
+}
+
+// StoreDocumentWithCodec saves a document encoded with the given codec in the
+// database, as done by StoreDocument.
+func (m *Manager) StoreDocumentWithCodec(ctx context.Context, docID string, r io.Reader, codec doc.Codec) (*StoreDocumentResult, error) {
+	entryList, err := doc.DecodeToPropertyList(docID, r, codec, m.docOptions()...)
 	if err != nil {
 		return nil, err
 	}
 
+	return m.storeDocument(ctx, docID, entryList)
+}
+
+// storeDocument saves the property list of a document, followed by its
+// manifest, as set by the write mode.
+func (m *Manager) storeDocument(ctx context.Context, docID string, entryList doc.PropertyEntryList) (*StoreDocumentResult, error) {
+	if m.conf.SaltedProperties {
+		entryList = doc.SaltPropertyList(entryList)
+	}
 	sort.Sort(entryList)
 
+	if m.conf.WriteMode == WriteModeAtomic {
+		return m.storeDocumentAtomic(ctx, docID, entryList)
+	}
+
+	entryList, err := saltValues(entryList)
+	if err != nil {
+		return nil, err
+	}
+
 	workers := worker.NewWriteWorkerPool(m.conf.NumberWorkers, m.client)
 	if err := workers.StartWorkers(ctx); err != nil {
 		return nil, err
@@ -151,13 +364,25 @@ func (m *Manager) StoreDocument(ctx context.Context, docID string, r io.Reader)
 
 	sort.Sort(resultHash)
 
-	manifest := &ObjectManifest{
-		ObjectID: docID,
-		Indexes:  resultHash.Indexes(),
-		Hash:     resultHash.Hash(),
-	}
+	return m.storeDocumentManifest(ctx, &ObjectManifest{
+		ObjectID:   docID,
+		Indexes:    resultHash.Indexes(),
+		Hash:       resultHash.Hash(),
+		MerkleRoot: resultHash.MerkleRoot(),
+	})
+}
 
-	index, err := m.writeDocumentManifest(ctx, manifest)
+// storeDocumentManifest writes the manifest of a document whose properties were
+// written concurrently.
+func (m *Manager) storeDocumentManifest(ctx context.Context, manifest *ObjectManifest) (*StoreDocumentResult, error) {
+	docID := manifest.ObjectID
+	unlock := m.locks.lock(docID)
+	index, err := m.latestManifestIndex(ctx, docID)
+	if err == nil {
+		manifest.PreviousIndex = index
+		index, err = m.writeDocumentManifest(ctx, manifest)
+	}
+	unlock()
 	if err != nil {
 		return nil, fmt.Errorf("unable to store manifes of object '%s': %v", docID, err)
 	}
@@ -165,21 +390,26 @@ func (m *Manager) StoreDocument(ctx context.Context, docID string, r io.Reader)
 	log.Printf("Object Write succesfull: index(%d) - keyID(%s)", index, docID)
 
 	return &StoreDocumentResult{
-		Index: index,
-		Hash:  manifest.Hash,
+		Index:      index,
+		Hash:       manifest.Hash,
+		MerkleRoot: manifest.MerkleRoot,
 	}, nil
 }
 
 // GetDocument allows the extraction of a document provided its global ID.
 func (m *Manager) GetDocument(ctx context.Context, docId string) (*GetDocumentResult, error) {
+	return m.GetDocumentWithCodec(ctx, docId, doc.JSONCodec)
+}
+
+// GetDocumentWithCodec allows the extraction of a document provided its global
+// ID, its payload being encoded with the given codec.
+func (m *Manager) GetDocumentWithCodec(ctx context.Context, docId string, codec doc.Codec) (*GetDocumentResult, error) {
 	docDetails, err := m.getDocumentDetails(ctx, docId)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Print("Reconstructing JSON object...")
-	rawObject := doc.PropertyListToRaw(docDetails.propertyEntryList)
-	payload, err := json.MarshalIndent(rawObject, "", "  ")
+	payload, err := docDetails.encode(codec)
 	if err != nil {
 		return nil, err
 	}
@@ -187,10 +417,11 @@ func (m *Manager) GetDocument(ctx context.Context, docId string) (*GetDocumentRe
 	log.Printf("Object Read succesfull: index(%d) - keyID(%s)", docDetails.objectManifestIndex, docDetails.objectManifestKey)
 
 	return &GetDocumentResult{
-		ID:      docId,
-		Payload: payload,
-		Index:   docDetails.objectManifestIndex,
-		Hash:    docDetails.propertyHashList.Hash(),
+		ID:         docId,
+		Payload:    payload,
+		Index:      docDetails.objectManifestIndex,
+		Hash:       docDetails.hash(),
+		MerkleRoot: docDetails.objectManifest.MerkleRoot,
 	}, nil
 }
 
@@ -199,28 +430,140 @@ func (m *Manager) GetDocument(ctx context.Context, docId string) (*GetDocumentRe
 type documentDetails struct {
 	objectManifestIndex uint64
 	objectManifestKey   string
+	objectManifestValue []byte
 	objectManifest      *ObjectManifest
+	indexBlocks         []uint64 // Indexes of the manifest's index blocks, if streamed.
 	propertyEntryList   doc.PropertyEntryList
 	propertyHashList    doc.PropertyHashList
 }
 
+// hash returns the global hash of the document. As a tombstone has no
+// properties, its hash is the digest of the tombstone manifest entry itself.
+func (d *documentDetails) hash() string {
+	if d.objectManifest.Deleted {
+		manifestHash := doc.CreatePropertyHash(d.objectManifestIndex, []byte(d.objectManifestKey), d.objectManifestValue)
+		return hex.EncodeToString(manifestHash.Hash)
+	}
+	return d.propertyHashList.Hash()
+}
+
+// payload reconstructs the raw JSON document from its properties.
+func (d *documentDetails) payload() ([]byte, error) {
+	return d.encode(doc.JSONCodec)
+}
+
+// encode reconstructs the document from its properties, encoded with the given
+// codec.
+func (d *documentDetails) encode(codec doc.Codec) ([]byte, error) {
+	if d.objectManifest.Deleted {
+		return nil, &NotFoundError{DocID: d.objectManifest.ObjectID}
+	}
+
+	log.Print("Reconstructing JSON object...")
+	rawObject := doc.PropertyListToRaw(d.propertyEntryList)
+	return codec.Encode(rawObject)
+}
+
 // getDocumentDetails fetches from the database the details of a given document.
 func (m *Manager) getDocumentDetails(ctx context.Context, docId string) (*documentDetails, error) {
-	docManifestKey := []byte("manifest/" + docId)
+	docManifestKey := manifestKey(docId)
 
 	log.Printf("Reading object objectManifest: DocumentID(%s)", docManifestKey)
 	docManifestItem, err := m.client.SafeGet(ctx, docManifestKey)
 	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, &NotFoundError{DocID: docId}
+		}
 		return nil, err
 	}
 	log.Printf("Object objectManifest: Index(%d) - Key(%s)", docManifestItem.Index, string(docManifestItem.Key))
 
+	docDetails, err := m.loadDocumentDetails(ctx, docManifestItem.Index, docManifestItem.Key, docManifestItem.Value)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkPreviousManifest(ctx, docDetails.objectManifest, docDetails.objectManifestIndex); err != nil {
+		return nil, err
+	}
+	if docDetails.objectManifest.Deleted {
+		return nil, &NotFoundError{DocID: docId}
+	}
+
+	return docDetails, nil
+}
+
+// readManifest reads, verified, the latest manifest of a document, without its
+// properties, returning it along with its index. Its indexes are resolved.
+func (m *Manager) readManifest(ctx context.Context, docID string) (*ObjectManifest, uint64, error) {
+	manifestItem, err := m.client.SafeGet(ctx, manifestKey(docID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, 0, &NotFoundError{DocID: docID}
+		}
+		return nil, 0, err
+	}
+	if !manifestItem.Verified {
+		return nil, 0, fmt.Errorf("manifest of document ID '%s' failed verification", docID)
+	}
+
+	manifest := &ObjectManifest{}
+	if err := json.Unmarshal(manifestItem.Value, manifest); err != nil {
+		return nil, 0, fmt.Errorf("unable to unmarshall object manifest: %v", err)
+	}
+	if err := checkManifestKey(manifest, manifestItem.Index, manifestKey(docID)); err != nil {
+		return nil, 0, err
+	}
+	if err := m.checkManifestSignature(manifest, manifestItem.Index); err != nil {
+		return nil, 0, err
+	}
+	if err := m.checkPreviousManifest(ctx, manifest, manifestItem.Index); err != nil {
+		return nil, 0, err
+	}
+	if err := manifest.resolveIndexes(manifestItem.Index); err != nil {
+		return nil, 0, err
+	}
+	if _, err := manifest.resolveIndexBlocks(manifestItem.Index, m.readEntry(ctx)); err != nil {
+		return nil, 0, err
+	}
+
+	return manifest, manifestItem.Index, nil
+}
+
+// latestManifestIndex returns the index of the latest manifest of a document,
+// tombstones included, or 0 if it has none.
+func (m *Manager) latestManifestIndex(ctx context.Context, docID string) (uint64, error) {
+	manifestItem, err := m.client.SafeGet(ctx, manifestKey(docID))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return manifestItem.Index, nil
+}
+
+// loadDocumentDetails fetches from the database the properties of a document,
+// provided a given version of its manifest.
+func (m *Manager) loadDocumentDetails(ctx context.Context, manifestIndex uint64, manifestItemKey, manifestValue []byte) (*documentDetails, error) {
 	objectManifest := &ObjectManifest{}
-	if err := json.Unmarshal(docManifestItem.Value, objectManifest); err != nil {
-		fmt.Printf("unmarshal failed")
+	if err := json.Unmarshal(manifestValue, objectManifest); err != nil {
+		return nil, fmt.Errorf("unable to unmarshall object manifest: %v", err)
+	}
+	if err := checkManifestKey(objectManifest, manifestIndex, manifestItemKey); err != nil {
 		return nil, err
 	}
-	log.Printf("Object objectManifest: Key(%s) - Indexes(%v)", string(docManifestItem.Key), objectManifest.Indexes)
+	if err := m.checkManifestSignature(objectManifest, manifestIndex); err != nil {
+		return nil, err
+	}
+	writtenInBatch := len(objectManifest.Offsets) > 0
+	if err := objectManifest.resolveIndexes(manifestIndex); err != nil {
+		return nil, err
+	}
+	indexBlocks, err := objectManifest.resolveIndexBlocks(manifestIndex, m.readEntry(ctx))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Object objectManifest: Key(%s) - Indexes(%v)", string(manifestItemKey), objectManifest.Indexes)
 
 	propertyList := doc.PropertyEntryList{}
 	propertyHashList := doc.PropertyHashList{}
@@ -231,20 +574,39 @@ func (m *Manager) getDocumentDetails(ctx context.Context, docId string) (*docume
 		}
 		log.Printf("Reading property: Index(%d) - Key(%s)", object.Index, object.Key)
 
-		propertyList = append(propertyList, doc.PropertyEntry{
+		// The property hash covers the salt of salted properties, while their
+		// value does not include it.
+		entry := doc.PropertyEntry{
 			KeyURI: string(object.Key),
 			Value:  object.Value.Payload,
-		})
+		}
+		if entry.IsSalted() {
+			if _, entry.Value, err = doc.SplitSalt(entry.Value); err != nil {
+				return nil, fmt.Errorf("invalid property at index %d: %v", object.Index, err)
+			}
+		}
+		propertyList = append(propertyList, entry)
 		hash := doc.CreatePropertyHash(object.Index, object.Key, object.Value.GetPayload())
 		propertyHashList = append(propertyHashList, hash)
 	}
 
 	sort.Sort(propertyHashList)
 
+	if writtenInBatch {
+		// Batch manifests have their global hash resolved from the properties,
+		// whose content must be the one recorded.
+		if objectManifest.ContentHash != propertyHashList.ContentHash() {
+			return nil, fmt.Errorf("properties of document ID '%s' do not match its manifest at index %d", objectManifest.ObjectID, manifestIndex)
+		}
+		objectManifest.Hash = propertyHashList.Hash()
+	}
+
 	return &documentDetails{
-		objectManifestIndex: docManifestItem.Index,
-		objectManifestKey:   string(docManifestItem.Key),
+		objectManifestIndex: manifestIndex,
+		objectManifestKey:   string(manifestItemKey),
+		objectManifestValue: manifestValue,
 		objectManifest:      objectManifest,
+		indexBlocks:         indexBlocks,
 		propertyEntryList:   propertyList,
 		propertyHashList:    propertyHashList,
 	}, nil
@@ -265,6 +627,12 @@ func (m *Manager) VerifyDocument(ctx context.Context, docID, globalHash string)
 	// Get the hash recorded in the manifest.
 	recordedHash := result.objectManifest.Hash
 
+	// Manifests written before Merkle roots were introduced do not record any.
+	recordedMerkleRoot := result.objectManifest.MerkleRoot
+	if recordedMerkleRoot != "" && recordedMerkleRoot != result.propertyHashList.MerkleRoot() {
+		return false, nil
+	}
+
 	if recordedHash == computedHash && computedHash == globalHash {
 		return true, nil
 	}
@@ -272,78 +640,52 @@ func (m *Manager) VerifyDocument(ctx context.Context, docID, globalHash string)
 	return false, nil
 }
 
-// UpdateDocument allows the update of a given property of a document.
+// UpdateDocument allows the update of a given property of a document, provided
+// its path as a JSON Pointer (e.g. "/address/city") and its new JSON value.
+// The property may be a new one, and its value may be of a different type or
+// a whole JSON object or array. The properties superseded by the new value are
+// dropped from the new manifest.
 // Here the underlying assumption for the implementation is that updates
 // are fairly rare and limited in scope.
-func (m *Manager) UpdateDocument(ctx context.Context, docID string, key string, value []byte) (*GetDocumentResult, error) {
-	docDetails, err := m.getDocumentDetails(ctx, docID)
+func (m *Manager) UpdateDocument(ctx context.Context, docID string, path string, value json.RawMessage) (*GetDocumentResult, error) {
+	return m.updateDocument(ctx, docID, ExpectedVersion{}, path, value)
+}
+
+// updateDocument updates a given property of a document, provided that the
+// latest version of the document is the expected one.
+func (m *Manager) updateDocument(ctx context.Context, docID string, expected ExpectedVersion, path string, value json.RawMessage) (*GetDocumentResult, error) {
+	rawValue, err := doc.DecodeValue(value, m.docOptions()...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid value of key=%s: %v", path, err)
 	}
 
-	propertyKey := docID + "/" + key
-
-	hashList := docDetails.propertyHashList
-	manifest := docDetails.objectManifest
+	unlock := m.locks.lock(docID)
+	defer unlock()
 
-	found := false
-	// Search for the property in the object manifest, and only replace that.
-	for i, hash := range hashList {
-		if hash.Key == propertyKey {
-			oldDBIndex := hash.Index
-
-			// Set the new property.
-			idx, err := m.client.SafeSet(ctx, []byte(propertyKey), value)
-			if err != nil {
-				return nil, err
-			}
-
-			// Update the hash list to include the new property.
-			newDBIndex := idx.Index
-			docDetails.propertyHashList[i] = doc.CreatePropertyHash(newDBIndex, []byte(key), value)
-
-			manifIndexes := manifest.Indexes
-			for pos, idx := range manifIndexes {
-				if idx == oldDBIndex {
-					manifIndexes[pos] = newDBIndex
-				}
-			}
-			// Update the manifest's hash list.
-			manifest.Indexes = manifIndexes
-
-			// Update the manifest's global hash.
-			manifest.Hash = hashList.Hash()
-
-			found = true
-			break
-		}
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Could not find and updated property.
-	if !found {
-		return nil, fmt.Errorf("document docID=%s does not have key=%s", key, docID)
+	if err := expected.check(docDetails); err != nil {
+		return nil, err
 	}
 
-	// Save the new object manifest.
-	index, err := m.writeDocumentManifest(ctx, manifest)
+	rawObject, err := doc.SetValue(doc.PropertyListToRaw(docDetails.propertyEntryList), path, rawValue)
 	if err != nil {
-		return nil, fmt.Errorf("unable to store manifes of object '%s': %v", docID, err)
+		return nil, fmt.Errorf("unable to update document docID=%s: %v", docID, err)
 	}
 
-	return &GetDocumentResult{
-		ID:    docID,
-		Index: index,
-		Hash:  manifest.Hash,
-	}, nil
+	return m.writeDocumentChanges(ctx, docDetails, doc.ObjectToPropertyList(docID, rawObject))
 }
 
 // writeDocumentManifest persists in the Database the document manifest descriptor.
 func (m *Manager) writeDocumentManifest(ctx context.Context, om *ObjectManifest) (uint64, error) {
-	objectManifestKey := []byte(fmt.Sprintf("manifest/%s", om.ObjectID))
+	objectManifestKey := manifestKey(om.ObjectID)
 
-	documentValue, err := json.Marshal(om)
+	documentValue, err := m.encodeManifest(om)
 	if err != nil {
-		return 0, fmt.Errorf("unable to marshall object maifest: %v", err)
+		return 0, err
 	}
 
 	idx, err := m.client.SafeSet(ctx, objectManifestKey, documentValue)
@@ -352,3 +694,36 @@ func (m *Manager) writeDocumentManifest(ctx context.Context, om *ObjectManifest)
 	}
 	return idx.Index, nil
 }
+
+// docOptions returns the options used to decode JSON payloads.
+func (m *Manager) docOptions() []doc.Option {
+	var opts []doc.Option
+	if m.conf.ExactNumbers {
+		opts = append(opts, doc.WithExactNumbers())
+	}
+	if m.conf.OrderedKeys {
+		opts = append(opts, doc.WithOrderedKeys())
+	}
+	return opts
+}
+
+// checkManifestKey ensures that a manifest describes the document whose
+// manifest key it was read from, so that it cannot be copied to another one.
+func checkManifestKey(om *ObjectManifest, manifestIndex uint64, key []byte) error {
+	if !bytes.Equal(key, manifestKey(om.ObjectID)) {
+		return fmt.Errorf("manifest %s at index %d describes document ID '%s'", key, manifestIndex, om.ObjectID)
+	}
+	return nil
+}
+
+// manifestKey returns the Database key holding the manifest of a document.
+func manifestKey(docID string) []byte {
+	return []byte("manifest/" + docID)
+}
+
+// indexBlockKey returns the key of the index blocks of a given document. Since
+// document IDs have no '/', it is never the key of a manifest, nor of a property,
+// whose keys end with their type.
+func indexBlockKey(docID string) []byte {
+	return []byte("manifest/" + docID + "/indexes")
+}
@@ -3,6 +3,9 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"sync"
 	"testing"
@@ -11,8 +14,11 @@ import (
 
 	immuschema "github.com/codenotary/immudb/pkg/api/schema"
 	immuclient "github.com/codenotary/immudb/pkg/client"
+	"github.com/codenotary/merkletree"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Ensure that the ImmuClientMock implements ImmuClient interface.
@@ -22,9 +28,15 @@ var _ immuclient.ImmuClient = &ImmuClientMock{}
 type ImmuClientMock struct {
 	mu *sync.RWMutex
 	immuclient.ImmuClient
-	safeSetFn func(ctx context.Context, key []byte, value []byte) (*immuclient.VerifiedIndex, error)
-	safeGetFn func(ctx context.Context, key []byte, opts ...grpc.CallOption) (*immuclient.VerifiedItem, error)
-	byIndexFn func(ctx context.Context, index uint64) (*immuschema.StructuredItem, error)
+	safeSetFn        func(ctx context.Context, key []byte, value []byte) (*immuclient.VerifiedIndex, error)
+	safeGetFn        func(ctx context.Context, key []byte, opts ...grpc.CallOption) (*immuclient.VerifiedItem, error)
+	byIndexFn        func(ctx context.Context, index uint64) (*immuschema.StructuredItem, error)
+	execAllOpsFn     func(ctx context.Context, ops *immuschema.Ops) (*immuschema.Index, error)
+	rawBySafeIndexFn func(ctx context.Context, index uint64) (*immuclient.VerifiedItem, error)
+	inclusionFn      func(ctx context.Context, index uint64) (*immuschema.InclusionProof, error)
+	historyFn        func(ctx context.Context, options *immuschema.HistoryOptions) (*immuschema.StructuredItemList, error)
+	currentRootFn    func(ctx context.Context) (*immuschema.Root, error)
+	consistencyFn    func(ctx context.Context, index uint64) (*immuschema.ConsistencyProof, error)
 }
 
 func (m *ImmuClientMock) SafeSet(ctx context.Context, key []byte, value []byte) (*immuclient.VerifiedIndex, error) {
@@ -45,6 +57,167 @@ func (m *ImmuClientMock) ByIndex(ctx context.Context, index uint64) (*immuschema
 	return m.byIndexFn(ctx, index)
 }
 
+func (m *ImmuClientMock) ExecAllOps(ctx context.Context, ops *immuschema.Ops) (*immuschema.Index, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.execAllOpsFn(ctx, ops)
+}
+
+func (m *ImmuClientMock) RawBySafeIndex(ctx context.Context, index uint64) (*immuclient.VerifiedItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.rawBySafeIndexFn(ctx, index)
+}
+
+func (m *ImmuClientMock) Inclusion(ctx context.Context, index uint64) (*immuschema.InclusionProof, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.inclusionFn(ctx, index)
+}
+
+func (m *ImmuClientMock) History(ctx context.Context, options *immuschema.HistoryOptions) (*immuschema.StructuredItemList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.historyFn(ctx, options)
+}
+
+func (m *ImmuClientMock) CurrentRoot(ctx context.Context) (*immuschema.Root, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.currentRootFn(ctx)
+}
+
+func (m *ImmuClientMock) Consistency(ctx context.Context, index uint64) (*immuschema.ConsistencyProof, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.consistencyFn(ctx, index)
+}
+
+// memoryStore emulates the append-only log of ImmuDB, where the position of an
+// entry in the log is its index, and every entry is a leaf of a Merkle tree.
+type memoryStore struct {
+	entries []*immuschema.StructuredItem
+	tree    merkletree.Storer
+}
+
+func (s *memoryStore) append(key, value []byte) uint64 {
+	index := uint64(len(s.entries))
+	item := &immuschema.StructuredItem{
+		Index: index,
+		Key:   append([]byte(nil), key...),
+		Value: &immuschema.Content{Payload: append([]byte(nil), value...), Timestamp: 1600000000 + index},
+	}
+	s.entries = append(s.entries, item)
+
+	leaf, _ := item.Hash()
+	var hash [sha256.Size]byte
+	copy(hash[:], leaf)
+	merkletree.AppendHash(s.tree, &hash)
+
+	return index
+}
+
+func (s *memoryStore) root() []byte {
+	root := merkletree.Root(s.tree)
+	return root[:]
+}
+
+func (s *memoryStore) latest(key []byte) (*immuschema.StructuredItem, error) {
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if bytes.Equal(s.entries[i].Key, key) {
+			return s.entries[i], nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "Key not found")
+}
+
+// newMemoryClientMock creates an ImmuDB client mock backed by a memoryStore.
+func newMemoryClientMock() (*ImmuClientMock, *memoryStore) {
+	store := &memoryStore{tree: merkletree.NewMemStore()}
+	return &ImmuClientMock{
+		mu: &sync.RWMutex{},
+		safeSetFn: func(ctx context.Context, key []byte, value []byte) (*immuclient.VerifiedIndex, error) {
+			return &immuclient.VerifiedIndex{Index: store.append(key, value), Verified: true}, nil
+		},
+		safeGetFn: func(ctx context.Context, key []byte, opts ...grpc.CallOption) (*immuclient.VerifiedItem, error) {
+			item, err := store.latest(key)
+			if err != nil {
+				return nil, err
+			}
+			return &immuclient.VerifiedItem{Key: item.Key, Value: item.Value.Payload, Index: item.Index, Verified: true}, nil
+		},
+		byIndexFn: func(ctx context.Context, index uint64) (*immuschema.StructuredItem, error) {
+			if index < uint64(len(store.entries)) {
+				return store.entries[index], nil
+			}
+			return nil, errors.New("not found")
+		},
+		execAllOpsFn: func(ctx context.Context, ops *immuschema.Ops) (*immuschema.Index, error) {
+			if err := ops.Validate(); err != nil {
+				return nil, err
+			}
+			var index uint64
+			for _, op := range ops.Operations {
+				kv := op.GetKVs()
+				index = store.append(kv.Key, kv.Value)
+			}
+			return &immuschema.Index{Index: index}, nil
+		},
+		rawBySafeIndexFn: func(ctx context.Context, index uint64) (*immuclient.VerifiedItem, error) {
+			if index >= uint64(len(store.entries)) {
+				return nil, errors.New("not found")
+			}
+			item, err := store.entries[index].ToItem()
+			if err != nil {
+				return nil, err
+			}
+			return &immuclient.VerifiedItem{Key: item.Key, Value: item.Value, Index: item.Index, Verified: true}, nil
+		},
+		inclusionFn: func(ctx context.Context, index uint64) (*immuschema.InclusionProof, error) {
+			if index >= uint64(len(store.entries)) {
+				return nil, errors.New("not found")
+			}
+			at := uint64(len(store.entries) - 1)
+			leaf, _ := store.entries[index].Hash()
+			return &immuschema.InclusionProof{
+				At:    at,
+				Index: index,
+				Root:  store.root(),
+				Leaf:  leaf,
+				Path:  merkletree.InclusionProof(store.tree, at, index).ToSlice(),
+			}, nil
+		},
+		historyFn: func(ctx context.Context, options *immuschema.HistoryOptions) (*immuschema.StructuredItemList, error) {
+			// Like ImmuDB, the most recent entries are returned first.
+			list := &immuschema.StructuredItemList{}
+			for i := len(store.entries) - 1; i >= 0; i-- {
+				if bytes.Equal(store.entries[i].Key, options.Key) {
+					list.Items = append(list.Items, store.entries[i])
+				}
+			}
+			return list, nil
+		},
+		currentRootFn: func(ctx context.Context) (*immuschema.Root, error) {
+			root := immuschema.NewRoot()
+			root.SetIndex(uint64(len(store.entries) - 1))
+			root.SetRoot(store.root())
+			return root, nil
+		},
+		consistencyFn: func(ctx context.Context, index uint64) (*immuschema.ConsistencyProof, error) {
+			at := uint64(len(store.entries) - 1)
+			if index > at {
+				return nil, errors.New("not found")
+			}
+			return &immuschema.ConsistencyProof{
+				First:      index,
+				Second:     at,
+				SecondRoot: store.root(),
+				Path:       merkletree.ConsistencyProof(store.tree, at, index).ToSlice(),
+			}, nil
+		},
+	}, store
+}
+
 func TestManagerStoreGetDocument(t *testing.T) {
 	type KeyValue struct {
 		Index uint64
@@ -101,39 +274,44 @@ func TestManagerStoreGetDocument(t *testing.T) {
 			}`),
 			expStoredProperties: []KeyValue{
 				{Key: "docID/secretBase/string", Value: []byte("Super tower")},
-				{Key: "docID/members/[0.3]/powers/[0.3]/string", Value: []byte("Radiation resistance")},
-				{Key: "docID/members/[0.3]/powers/[1.3]/string", Value: []byte("Turning tiny")},
-				{Key: "docID/members/[0.3]/powers/[2.3]/string", Value: []byte("Radiation blast")},
-				{Key: "docID/members/[0.3]/name/string", Value: []byte("Molecule Man")},
-				{Key: "docID/members/[0.3]/age/float64", Value: doc.Float64ToBinary(29)},
-				{Key: "docID/members/[0.3]/secretIdentity/string", Value: []byte("Dan Jukes")},
-				{Key: "docID/members/[1.3]/name/string", Value: []byte("Madame Uppercut")},
-				{Key: "docID/members/[1.3]/age/float64", Value: doc.Float64ToBinary(39)},
-				{Key: "docID/members/[1.3]/secretIdentity/string", Value: []byte("Jane Wilson")},
+				{Key: "docID/members/[0]/powers/[0]/string", Value: []byte("Radiation resistance")},
+				{Key: "docID/members/[0]/powers/[1]/string", Value: []byte("Turning tiny")},
+				{Key: "docID/members/[0]/powers/[2]/string", Value: []byte("Radiation blast")},
+				{Key: "docID/members/[0]/name/string", Value: []byte("Molecule Man")},
+				{Key: "docID/members/[0]/age/float64", Value: doc.Float64ToBinary(29)},
+				{Key: "docID/members/[0]/secretIdentity/string", Value: []byte("Dan Jukes")},
+				{Key: "docID/members/[1]/name/string", Value: []byte("Madame Uppercut")},
+				{Key: "docID/members/[1]/age/float64", Value: doc.Float64ToBinary(39)},
+				{Key: "docID/members/[1]/secretIdentity/string", Value: []byte("Jane Wilson")},
 				{Key: "docID/active/bool", Value: []byte("true")},
-				{Key: "docID/members/[1.3]/powers/[1.3]/string", Value: []byte("Damage resistance")},
-				{Key: "docID/members/[1.3]/powers/[2.3]/string", Value: []byte("Superhuman reflexes")},
-				{Key: "docID/members/[2.3]/age/float64", Value: doc.Float64ToBinary(1000)},
-				{Key: "docID/members/[2.3]/secretIdentity/string", Value: []byte("Unknown")},
-				{Key: "docID/members/[2.3]/powers/[0.5]/string", Value: []byte("Immortality")},
-				{Key: "docID/members/[2.3]/powers/[1.5]/string", Value: []byte("Heat Immunity")},
-				{Key: "docID/members/[2.3]/powers/[2.5]/string", Value: []byte("Inferno")},
-				{Key: "docID/members/[2.3]/powers/[3.5]/string", Value: []byte("Teleportation")},
-				{Key: "docID/members/[2.3]/powers/[4.5]/string", Value: []byte("Interdimensional travel")},
-				{Key: "docID/members/[2.3]/name/string", Value: []byte("Eternal Flame")},
+				{Key: "docID/members/[1]/powers/[1]/string", Value: []byte("Damage resistance")},
+				{Key: "docID/members/[1]/powers/[2]/string", Value: []byte("Superhuman reflexes")},
+				{Key: "docID/members/[2]/age/float64", Value: doc.Float64ToBinary(1000)},
+				{Key: "docID/members/[2]/secretIdentity/string", Value: []byte("Unknown")},
+				{Key: "docID/members/[2]/powers/[0]/string", Value: []byte("Immortality")},
+				{Key: "docID/members/[2]/powers/[1]/string", Value: []byte("Heat Immunity")},
+				{Key: "docID/members/[2]/powers/[2]/string", Value: []byte("Inferno")},
+				{Key: "docID/members/[2]/powers/[3]/string", Value: []byte("Teleportation")},
+				{Key: "docID/members/[2]/powers/[4]/string", Value: []byte("Interdimensional travel")},
+				{Key: "docID/members/[2]/name/string", Value: []byte("Eternal Flame")},
 				{Key: "docID/formed/float64", Value: doc.Float64ToBinary(2016)},
 				{Key: "docID/squadName/string", Value: []byte("Super hero squad")},
 				{Key: "docID/homeTown/string", Value: []byte("Metro City")},
-				{Key: "docID/members/[1.3]/powers/[0.3]/string", Value: []byte("Million tonne punch")},
+				{Key: "docID/members/[1]/powers/[0]/string", Value: []byte("Million tonne punch")},
+				{Key: "docID/members/array", Value: []byte("3")},
+				{Key: "docID/members/[0]/powers/array", Value: []byte("3")},
+				{Key: "docID/members/[1]/powers/array", Value: []byte("3")},
+				{Key: "docID/members/[2]/powers/array", Value: []byte("5")},
 				{Key: "manifest/docID", Value: []byte(`{"id":"docID",
-					"indexes":[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24],
-					"hash":"51175166a32086593b4bdc5d992acca5b45fbaef487c297759e986e603227f27"
+					"indexes":[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28],
+					"hash":"669b651b19771fb0c2b6fe1e4a6071c31a16418af621a957f94b87225b9179d4"
 				}`)},
 			},
 			expObjectManifest: &ObjectManifest{
-				ObjectID: "docID",
-				Indexes:  []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24},
-				Hash:     "51175166a32086593b4bdc5d992acca5b45fbaef487c297759e986e603227f27",
+				ObjectID:   "docID",
+				Indexes:    []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28},
+				Hash:       "669b651b19771fb0c2b6fe1e4a6071c31a16418af621a957f94b87225b9179d4",
+				MerkleRoot: "5c622e6601addc3bea26e3f5e1f905f510aa9da4d112c206c3523e6cc5db5b40",
 			},
 		},
 		"Stored document #2": {
@@ -145,21 +323,23 @@ func TestManagerStoreGetDocument(t *testing.T) {
 				]
 			}`),
 			expStoredProperties: []KeyValue{
-				{Key: "docID/people/[0.3]/id/float64", Value: doc.Float64ToBinary(0)},
-				{Key: "docID/people/[0.3]/name/string", Value: []byte("Monroe Roth")},
-				{Key: "docID/people/[1.3]/id/float64", Value: doc.Float64ToBinary(1)},
-				{Key: "docID/people/[1.3]/name/string", Value: []byte("Mullen Rhodes")},
-				{Key: "docID/people/[2.3]/id/float64", Value: doc.Float64ToBinary(2)},
-				{Key: "docID/people/[2.3]/name/string", Value: []byte("Mcclure Welch")},
+				{Key: "docID/people/[0]/id/float64", Value: doc.Float64ToBinary(0)},
+				{Key: "docID/people/[0]/name/string", Value: []byte("Monroe Roth")},
+				{Key: "docID/people/[1]/id/float64", Value: doc.Float64ToBinary(1)},
+				{Key: "docID/people/[1]/name/string", Value: []byte("Mullen Rhodes")},
+				{Key: "docID/people/[2]/id/float64", Value: doc.Float64ToBinary(2)},
+				{Key: "docID/people/[2]/name/string", Value: []byte("Mcclure Welch")},
+				{Key: "docID/people/array", Value: []byte("3")},
 				{Key: "manifest/docID", Value: []byte(`{"id":"docID",
-					"indexes":[0,1,2,3,4,5],
-					"hash":"61486e6661a9fe498c8ff50b0f5232e3d2979328faa6316346795067acc0eee6"
+					"indexes":[0,1,2,3,4,5,6],
+					"hash":"42b2e5bad77019e0613724add67a1a847add3f041c9de325cd8c39204a3b9af8"
 				}`)},
 			},
 			expObjectManifest: &ObjectManifest{
-				ObjectID: "docID",
-				Indexes:  []uint64{0, 1, 2, 3, 4, 5},
-				Hash:     "61486e6661a9fe498c8ff50b0f5232e3d2979328faa6316346795067acc0eee6",
+				ObjectID:   "docID",
+				Indexes:    []uint64{0, 1, 2, 3, 4, 5, 6},
+				Hash:       "42b2e5bad77019e0613724add67a1a847add3f041c9de325cd8c39204a3b9af8",
+				MerkleRoot: "ee9c16d0b41642f8193ec81f4b65c95fea00c9844128c81629e531c91cec996d",
 			},
 		},
 	}
@@ -187,7 +367,7 @@ func TestManagerStoreGetDocument(t *testing.T) {
 							}, nil
 						}
 					}
-					return nil, errors.New("not found")
+					return nil, status.Error(codes.NotFound, "Key not found")
 				},
 				byIndexFn: func(ctx context.Context, index uint64) (*immuschema.StructuredItem, error) {
 					if index < uint64(len(gotStoredProperties)-1) {
@@ -236,3 +416,240 @@ func TestManagerStoreGetDocument(t *testing.T) {
 		})
 	}
 }
+
+func TestManagerUpdateDocument(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`{"name": "John", "nickname": null, "tags": ["a"]}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path       string
+		value      string
+		expPayload string
+		expWritten int
+	}{
+		{
+			// Change of a property's type.
+			path:       "/nickname",
+			value:      `"Johnny"`,
+			expPayload: `{"name": "John", "nickname": "Johnny", "tags": ["a"]}`,
+			expWritten: 1,
+		},
+		{
+			// New property, within a new object.
+			path:       "/address/city",
+			value:      `"Lisbon"`,
+			expPayload: `{"name": "John", "nickname": "Johnny", "tags": ["a"], "address": {"city": "Lisbon"}}`,
+			expWritten: 1,
+		},
+		{
+			// Scalar property replaced by an object.
+			path:       "/name",
+			value:      `{"first": "John", "last": "Doe"}`,
+			expPayload: `{"name": {"first": "John", "last": "Doe"}, "nickname": "Johnny", "tags": ["a"], "address": {"city": "Lisbon"}}`,
+			expWritten: 2,
+		},
+		{
+			// Object replaced by a scalar property.
+			path:       "/name",
+			value:      `"John Doe"`,
+			expPayload: `{"name": "John Doe", "nickname": "Johnny", "tags": ["a"], "address": {"city": "Lisbon"}}`,
+			expWritten: 1,
+		},
+	}
+
+	for _, test := range tests {
+		numEntries := len(store.entries)
+
+		updateResult, err := manager.UpdateDocument(context.Background(), "docID", test.path, json.RawMessage(test.value))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Only the new properties are written, along with the manifest.
+		assert.Len(t, store.entries, numEntries+test.expWritten+1)
+
+		getResult, err := manager.GetDocument(context.Background(), "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.JSONEq(t, test.expPayload, string(getResult.Payload))
+		assert.Equal(t, updateResult.Hash, getResult.Hash)
+
+		// The manifest only references the properties of the document.
+		details, err := manager.getDocumentDetails(context.Background(), "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		entryList, err := doc.RawToPropertyList("docID", bytes.NewReader([]byte(test.expPayload)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.ElementsMatch(t, entryList, details.propertyEntryList)
+	}
+
+	_, err := manager.UpdateDocument(context.Background(), "docID", "/name", json.RawMessage(`"John`))
+	assert.EqualError(t, err, "invalid value of key=/name: unexpected EOF")
+
+	_, err = manager.UpdateDocument(context.Background(), "docID", "/tags/5", json.RawMessage(`"b"`))
+	assert.EqualError(t, err, "unable to update document docID=docID: array index '5' out of bounds")
+}
+
+func TestManagerStoreGetDocument_ExactNumbers(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1).WithExactNumbers(true),
+		client: clientMock,
+	}
+
+	jsonPayload := []byte(`{"index": 18446744073709551615, "id": 9007199254740993, "price": 19.99}`)
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updateResult, err := manager.UpdateDocument(context.Background(), "docID", "/total", json.RawMessage(`12345678901234567890`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.NotEqual(t, storeResult.Hash, updateResult.Hash)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{
+  "id": 9007199254740993,
+  "index": 18446744073709551615,
+  "price": 19.99,
+  "total": 12345678901234567890
+}`, string(getResult.Payload))
+	assert.Equal(t, updateResult.Hash, getResult.Hash)
+}
+
+func TestManagerStoreGetDocument_OrderedKeys(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1).WithOrderedKeys(true),
+		client: clientMock,
+	}
+
+	jsonPayload := []byte(`{"name": "John", "address": {"zip": "10001", "city": "New York"}, "age": 30}`)
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updateResult, err := manager.UpdateDocument(context.Background(), "docID", "/email", json.RawMessage(`"john@example.com"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{
+  "name": "John",
+  "address": {
+    "zip": "10001",
+    "city": "New York"
+  },
+  "age": 30,
+  "email": "john@example.com"
+}`, string(getResult.Payload))
+	assert.Equal(t, updateResult.Hash, getResult.Hash)
+}
+
+func TestManagerStoreGetDocument_Codecs(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	yamlPayload := []byte("name: John\ntags:\n  - a\n  - b\n")
+	storeResult, err := manager.StoreDocumentWithCodec(context.Background(), "docID", bytes.NewReader(yamlPayload), doc.YAMLCodec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The document's properties do not depend on the format.
+	jsonResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{
+  "name": "John",
+  "tags": [
+    "a",
+    "b"
+  ]
+}`, string(jsonResult.Payload))
+	assert.Equal(t, storeResult.Hash, jsonResult.Hash)
+
+	cborResult, err := manager.GetDocumentWithCodec(context.Background(), "docID", doc.CBORCodec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "a2646e616d65644a6f686e64746167738261616162", hex.EncodeToString(cborResult.Payload))
+	assert.Equal(t, storeResult.Hash, cborResult.Hash)
+
+	_, err = manager.StoreDocumentWithCodec(context.Background(), "docID", bytes.NewReader([]byte{0xc1}), doc.MsgPackCodec)
+	assert.EqualError(t, err, "unable to unmarshall payload: invalid MessagePack format 0xc1")
+}
+
+func TestManagerStoreGetDocument_TopLevelValues(t *testing.T) {
+	payloads := []string{`"John"`, `30`, `true`, `null`, `{}`, `[]`, `["a", 1, false]`, `[[1, [2]], [], [{}]]`}
+
+	for _, writeMode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		clientMock, _ := newMemoryClientMock()
+		manager := Manager{
+			conf:   *DefaultConfig().WithNumberWorkers(1).WithWriteMode(writeMode),
+			client: clientMock,
+		}
+
+		for _, payload := range payloads {
+			storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(payload)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			getResult, err := manager.GetDocument(context.Background(), "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, payload, string(getResult.Payload))
+			assert.Equal(t, storeResult.Hash, getResult.Hash)
+		}
+	}
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`["a", "b"]`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.UpdateDocument(context.Background(), "docID", "/-", json.RawMessage(`"c"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.PatchDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`[{"op": "replace", "path": "/0", "value": 1}]`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `[1, "b", "c"]`, string(getResult.Payload))
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// PutAttachment attaches binary content to a document under a given name,
+// replacing any previous attachment of the same name. The content is streamed
+// into chunk properties of the configured size, followed by the attachment's
+// description, and a new manifest of the document covering all of them is
+// written. Like concurrent writes, a failure halfway leaves the written chunks
+// orphaned, the document remaining at its previous version, unless the atomic
+// write mode is configured, in which case the whole content is gathered and
+// written in a single batch along with the manifest. Attachments are never
+// salted, whatever the configuration, hence the digests of a small and
+// guessable attachment do not hide its content.
+func (m *Manager) PutAttachment(ctx context.Context, docID, name string, r io.Reader) (*GetDocumentResult, error) {
+	chunkSize := m.conf.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = doc.DefaultChunkSize
+	}
+
+	unlock := m.locks.lock(docID)
+	defer unlock()
+
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := docDetails.keptProperties(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.conf.WriteMode == WriteModeAtomic {
+		return m.putAttachmentAtomic(ctx, docDetails, committed, name, r, chunkSize)
+	}
+
+	var hashList doc.PropertyHashList
+	info, err := doc.SplitAttachment(r, chunkSize, func(chunk int, data []byte) error {
+		hash, err := m.writeProperty(ctx, doc.PropertyChunk(docID, name, chunk, data))
+		if err != nil {
+			return err
+		}
+		hashList = append(hashList, hash)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to store attachment '%s' of document ID '%s': %v", name, docID, err)
+	}
+
+	for _, hash := range hashList {
+		info.Indexes = append(info.Indexes, hash.Index)
+	}
+	hash, err := m.writeProperty(ctx, doc.PropertyAttachment(docID, name, info))
+	if err != nil {
+		return nil, fmt.Errorf("unable to store attachment '%s' of document ID '%s': %v", name, docID, err)
+	}
+	hashList = append(hashList, hash)
+	hashList = append(hashList, committed...)
+	sort.Sort(hashList)
+
+	manifest := &ObjectManifest{
+		ObjectID:      docID,
+		Indexes:       hashList.Indexes(),
+		Hash:          hashList.Hash(),
+		MerkleRoot:    hashList.MerkleRoot(),
+		PreviousIndex: docDetails.objectManifestIndex,
+	}
+	index, err := m.writeDocumentManifest(ctx, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("unable to store manifes of object '%s': %v", docID, err)
+	}
+
+	log.Printf("Attachment Write succesfull: index(%d) - keyID(%s) - name(%s) - chunks(%d)", index, docID, name, len(info.Indexes))
+
+	return &GetDocumentResult{
+		ID:         docID,
+		Index:      index,
+		Hash:       manifest.Hash,
+		MerkleRoot: manifest.MerkleRoot,
+	}, nil
+}
+
+// putAttachmentAtomic writes the chunks of an attachment, followed by its
+// description and the new manifest of the document, in a single batch. As the
+// indexes of the chunks are only known after the batch is committed, the
+// description records their offsets instead. The caller must hold the lock of
+// the document.
+func (m *Manager) putAttachmentAtomic(ctx context.Context, docDetails *documentDetails, committed doc.PropertyHashList, name string, r io.Reader, chunkSize int) (*GetDocumentResult, error) {
+	docID := docDetails.objectManifest.ObjectID
+
+	var pending doc.PropertyEntryList
+	info, err := doc.SplitAttachment(r, chunkSize, func(chunk int, data []byte) error {
+		pending = append(pending, doc.PropertyChunk(docID, name, chunk, append([]byte(nil), data...)))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to store attachment '%s' of document ID '%s': %v", name, docID, err)
+	}
+
+	// The pending properties are written in order, the description right
+	// after the chunks.
+	for chunk := range pending {
+		info.Offsets = append(info.Offsets, uint64(len(pending)-chunk))
+	}
+	pending = append(pending, doc.PropertyAttachment(docID, name, info))
+
+	result, err := m.commitBatch(ctx, []*batchDocument{{
+		docID:         docID,
+		previousIndex: docDetails.objectManifestIndex,
+		committed:     committed,
+		pending:       pending,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to store attachment '%s' of document ID '%s': %w", name, docID, err)
+	}
+
+	log.Printf("Attachment Write succesfull: index(%d) - keyID(%s) - name(%s) - chunks(%d)", result.Documents[docID].Index, docID, name, len(info.Offsets))
+
+	return &GetDocumentResult{
+		ID:         docID,
+		Index:      result.Documents[docID].Index,
+		Hash:       result.Documents[docID].Hash,
+		MerkleRoot: result.Documents[docID].MerkleRoot,
+	}, nil
+}
+
+// keptProperties returns the properties of a document kept when attaching
+// content under a given name: every property but the previous attachment of
+// the same name, if any, and its chunks.
+func (d *documentDetails) keptProperties(name string) (doc.PropertyHashList, error) {
+	attachmentKey := doc.AttachmentKey(d.objectManifest.ObjectID, name)
+
+	replaced := map[uint64]bool{}
+	for _, hash := range d.propertyHashList {
+		if hash.Key != attachmentKey {
+			continue
+		}
+		for _, entry := range d.propertyEntryList {
+			if entry.KeyURI != attachmentKey {
+				continue
+			}
+			previous, err := doc.ParseAttachment(entry.Value, hash.Index)
+			if err != nil {
+				return nil, err
+			}
+			for _, index := range previous.Indexes {
+				replaced[index] = true
+			}
+		}
+	}
+
+	var kept doc.PropertyHashList
+	for _, hash := range d.propertyHashList {
+		if !replaced[hash.Index] && hash.Key != attachmentKey {
+			kept = append(kept, hash)
+		}
+	}
+	return kept, nil
+}
+
+// GetAttachment streams the content of a named attachment of a document to
+// the given writer. The document's manifest, the attachment's description and
+// every chunk are read verified, the description and each chunk being checked
+// to belong to the latest version of the document, and the content to match
+// the attachment's size and digest. Chunks are written as they are verified,
+// hence the content must be discarded if an error is returned.
+func (m *Manager) GetAttachment(ctx context.Context, docID, name string, w io.Writer) (*doc.AttachmentInfo, error) {
+	manifest, _, err := m.readManifest(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Deleted {
+		return nil, &NotFoundError{DocID: docID}
+	}
+
+	indexes := make(map[uint64]bool, len(manifest.Indexes))
+	for _, index := range manifest.Indexes {
+		indexes[index] = true
+	}
+
+	// The latest entry of the attachment's key may not belong to the latest
+	// version of the document, e.g. if written by a failed attachment.
+	attachmentKey := doc.AttachmentKey(docID, name)
+	items, err := m.client.History(ctx, &immuschema.HistoryOptions{Key: []byte(attachmentKey)})
+	if err != nil {
+		return nil, err
+	}
+	attachmentIndex, found := uint64(0), false
+	for _, item := range items.GetItems() {
+		if indexes[item.Index] {
+			attachmentIndex, found = item.Index, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("document ID '%s' does not have attachment '%s'", docID, name)
+	}
+	value, err := m.readChunk(ctx, attachmentIndex, attachmentKey)
+	if err != nil {
+		return nil, err
+	}
+	info, err := doc.ParseAttachment(value, attachmentIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := doc.NewAttachmentVerifier(info)
+	for chunk, index := range info.Indexes {
+		if !indexes[index] {
+			return nil, fmt.Errorf("chunk #%d of attachment '%s' is not part of document ID '%s'", chunk, name, docID)
+		}
+		data, err := m.readChunk(ctx, index, doc.ChunkKey(docID, name, chunk))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := verifier.Write(data); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := verifier.Verify(); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// writeProperty writes a single property, returning its hash.
+func (m *Manager) writeProperty(ctx context.Context, entry doc.PropertyEntry) (*doc.PropertyHash, error) {
+	index, err := m.client.SafeSet(ctx, []byte(entry.KeyURI), entry.Value)
+	if err != nil {
+		return nil, err
+	}
+	return doc.CreatePropertyHash(index.Index, []byte(entry.KeyURI), entry.Value), nil
+}
+
+// readChunk reads, verified, the property of an attachment stored at a given
+// index, either a chunk or its description, checking its key.
+func (m *Manager) readChunk(ctx context.Context, index uint64, key string) ([]byte, error) {
+	itemKey, value, err := m.readEntry(ctx)(index)
+	if err != nil {
+		return nil, err
+	}
+	if string(itemKey) != key {
+		return nil, fmt.Errorf("entry at index %d has key '%s', expected '%s'", index, itemKey, key)
+	}
+	return value, nil
+}
+
+// readEntry returns a function reading, verified, the key and value of the
+// entry stored at a given index.
+func (m *Manager) readEntry(ctx context.Context) func(index uint64) ([]byte, []byte, error) {
+	return func(index uint64) ([]byte, []byte, error) {
+		item, err := m.client.RawBySafeIndex(ctx, index)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !item.Verified {
+			return nil, nil, fmt.Errorf("entry at index %d failed verification", index)
+		}
+
+		structuredItem, err := (&immuschema.Item{Key: item.Key, Value: item.Value, Index: index}).ToSItem()
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to unmarshall entry at index %d: %v", index, err)
+		}
+		return item.Key, structuredItem.Value.Payload, nil
+	}
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerPutGetAttachment(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1).WithChunkSize(4),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	storeResult, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name":"John"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	putResult, err := manager.PutAttachment(ctx, "docID", "scans/id.png", bytes.NewReader([]byte("\x89PNG binary")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.NotEqual(t, storeResult.Hash, putResult.Hash)
+
+	var content bytes.Buffer
+	info, err := manager.GetAttachment(ctx, "docID", "scans/id.png", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "\x89PNG binary", content.String())
+	assert.Equal(t, int64(11), info.Size)
+	assert.Equal(t, 3, len(info.Indexes))
+
+	// The document itself is unchanged, while its hash covers the attachment.
+	getResult, err := manager.GetDocument(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name":"John"}`, string(getResult.Payload))
+	assert.Equal(t, putResult.Hash, getResult.Hash)
+	verified, err := manager.VerifyDocument(ctx, "docID", putResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, verified)
+
+	// Attachments survive updates of the document.
+	if _, err := manager.UpdateDocument(ctx, "docID", "/name", json.RawMessage(`"Jane"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content.Reset()
+	if _, err := manager.GetAttachment(ctx, "docID", "scans/id.png", &content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "\x89PNG binary", content.String())
+
+	// Replacing an attachment drops its previous chunks from the manifest.
+	replaceResult, err := manager.PutAttachment(ctx, "docID", "scans/id.png", bytes.NewReader([]byte("GIF")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	details, err := manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 3, len(details.propertyHashList))
+	assert.Equal(t, replaceResult.Hash, details.objectManifest.Hash)
+	content.Reset()
+	if _, err := manager.GetAttachment(ctx, "docID", "scans/id.png", &content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "GIF", content.String())
+
+	_, err = manager.GetAttachment(ctx, "docID", "other.pdf", &content)
+	assert.EqualError(t, err, "document ID 'docID' does not have attachment 'other.pdf'")
+
+	// A tampered chunk is detected.
+	chunkKey := []byte(doc.ChunkKey("docID", "scans/id.png", 0))
+	for _, entry := range store.entries {
+		if bytes.Equal(entry.Key, chunkKey) {
+			entry.Value.Payload = []byte("FIG")
+		}
+	}
+	_, err = manager.GetAttachment(ctx, "docID", "scans/id.png", &content)
+	assert.Contains(t, err.Error(), "attachment digest mismatch")
+
+	if _, err := manager.DeleteDocument(ctx, "docID"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = manager.GetAttachment(ctx, "docID", "scans/id.png", &content)
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = manager.PutAttachment(ctx, "missingID", "scans/id.png", bytes.NewReader([]byte("GIF")))
+	assert.Error(t, err)
+}
+
+func TestManagerPutAttachment_UnsortedManifest(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name":"John","age":30}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.PutAttachment(ctx, "docID", "id.png", bytes.NewReader([]byte("PNG"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A manifest whose indexes are not sorted, listing the attachment first.
+	details, err := manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest := *details.objectManifest
+	manifest.Indexes = []uint64{4, 3, 1, 0}
+	if _, err := manager.writeDocumentManifest(ctx, &manifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replacing the attachment drops its previous description, and keeps
+	// every other property.
+	if _, err := manager.PutAttachment(ctx, "docID", "id.png", bytes.NewReader([]byte("GIF"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	details, err = manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{0, 1, 7, 8}, details.propertyHashList.Indexes())
+	assert.Equal(t, doc.AttachmentKey("docID", "id.png"), string(store.entries[8].Key))
+
+	getResult, err := manager.GetDocument(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name":"John","age":30}`, string(getResult.Payload))
+}
+
+func TestManagerPutAttachment_SaltedProperties(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1).WithSaltedProperties(true),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name":"John"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.PutAttachment(ctx, "docID", "id.png", bytes.NewReader([]byte("PNG"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Attachments are never salted, and survive updates of a salted document.
+	chunk := store.entries[len(store.entries)-3]
+	assert.Equal(t, doc.ChunkKey("docID", "id.png", 0), string(chunk.Key))
+	assert.Equal(t, []byte("PNG"), chunk.Value.Payload)
+
+	if _, err := manager.UpdateDocument(ctx, "docID", "/name", json.RawMessage(`"Jane"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var content bytes.Buffer
+	if _, err := manager.GetAttachment(ctx, "docID", "id.png", &content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "PNG", content.String())
+
+	getResult, err := manager.GetDocument(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name":"Jane"}`, string(getResult.Payload))
+}
+
+func TestManagerPutAttachment_Atomic(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic).WithChunkSize(4),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name":"John"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	putResult, err := manager.PutAttachment(ctx, "docID", "scans/id.png", bytes.NewReader([]byte("\x89PNG binary")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The chunks, the description and the manifest are written in a single
+	// batch, the description recording the offsets of the chunks.
+	assert.Len(t, store.entries, 7)
+	attachment := store.entries[5]
+	assert.Equal(t, doc.AttachmentKey("docID", "scans/id.png"), string(attachment.Key))
+	assert.Contains(t, string(attachment.Value.Payload), `"offsets":[3,2,1]`)
+
+	var content bytes.Buffer
+	info, err := manager.GetAttachment(ctx, "docID", "scans/id.png", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "\x89PNG binary", content.String())
+	assert.Equal(t, []uint64{2, 3, 4}, info.Indexes)
+
+	verified, err := manager.VerifyDocument(ctx, "docID", putResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, verified)
+
+	// Replacing an attachment drops its previous chunks from the manifest.
+	if _, err := manager.PutAttachment(ctx, "docID", "scans/id.png", bytes.NewReader([]byte("GIF"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	details, err := manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{0, 7, 8}, details.propertyHashList.Indexes())
+	content.Reset()
+	if _, err := manager.GetAttachment(ctx, "docID", "scans/id.png", &content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "GIF", content.String())
+}
+
+func TestManagerGetAttachment_OrphanedDescription(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name":"John"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.PutAttachment(ctx, "docID", "id.png", bytes.NewReader([]byte("PNG"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A description left behind by a failed attachment, or written by anyone
+	// else, is not part of the latest version of the document.
+	chunkIndex := store.append([]byte(doc.ChunkKey("docID", "id.png", 0)), []byte("GIF"))
+	orphan := doc.PropertyAttachment("docID", "id.png", &doc.AttachmentInfo{
+		Size:      3,
+		ChunkSize: 3,
+		Digest:    "digest",
+		Indexes:   []uint64{chunkIndex},
+	})
+	store.append([]byte(orphan.KeyURI), orphan.Value)
+
+	var content bytes.Buffer
+	if _, err := manager.GetAttachment(ctx, "docID", "id.png", &content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "PNG", content.String())
+}
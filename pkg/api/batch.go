@@ -0,0 +1,279 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// batchDocument represents the writes of a single document gathered in a batch.
+type batchDocument struct {
+	docID         string
+	expected      ExpectedVersion       // Version the document must be at when written, if any.
+	previousIndex uint64                // Index of the manifest on which the new one is based, if any.
+	committed     doc.PropertyHashList  // Properties already stored, kept by the new manifest.
+	pending       doc.PropertyEntryList // Properties written along with the new manifest, in order.
+}
+
+// newBatchDocument returns the writes of a new version of a document, provided
+// its full property list and the version on which it is based, if any. Only the
+// properties whose key or value changed are written, the remaining ones keeping
+// their current entries, while the properties missing from the list are dropped
+// from the new manifest. The properties are salted if requested, or if the
+// document already has salted properties.
+func newBatchDocument(docID string, base *documentDetails, entryList doc.PropertyEntryList, salted bool) *batchDocument {
+	if salted || (base != nil && base.hasSaltedProperties()) {
+		entryList = doc.SaltPropertyList(entryList)
+	}
+
+	sort.Sort(entryList)
+
+	batchDoc := &batchDocument{docID: docID}
+	if base == nil {
+		batchDoc.pending = entryList
+		return batchDoc
+	}
+	batchDoc.previousIndex = base.objectManifestIndex
+
+	currentValues := make(map[string][]byte, len(base.propertyEntryList))
+	for _, entry := range base.propertyEntryList {
+		currentValues[entry.KeyURI] = entry.Value
+	}
+	currentHashes := make(map[string]*doc.PropertyHash, len(base.propertyHashList))
+	for _, hash := range base.propertyHashList {
+		currentHashes[hash.Key] = hash
+	}
+
+	// Attachments are not part of the document's raw object, hence they are
+	// kept as they are.
+	for _, entry := range base.propertyEntryList {
+		if entry.IsAttachment() {
+			batchDoc.committed = append(batchDoc.committed, currentHashes[entry.KeyURI])
+		}
+	}
+
+	for _, entry := range entryList {
+		value, ok := currentValues[entry.KeyURI]
+		if ok && bytes.Equal(value, entry.Value) {
+			batchDoc.committed = append(batchDoc.committed, currentHashes[entry.KeyURI])
+			continue
+		}
+		batchDoc.pending = append(batchDoc.pending, entry)
+	}
+
+	return batchDoc
+}
+
+// CommitResult represents the insertion result of a batch of documents.
+type CommitResult struct {
+	Index     uint64                          // Index of the last entry of the batch.
+	Hash      string                          // Hash of the documents' hashes, sorted by document ID.
+	Documents map[string]*StoreDocumentResult // Insertion result of each document.
+}
+
+// storeDocumentAtomic saves the property list of a document together with its
+// manifest in a single batch operation. Either every entry is committed or none
+// is, so a failure never leaves orphaned properties behind.
+func (m *Manager) storeDocumentAtomic(ctx context.Context, docID string, entryList doc.PropertyEntryList) (*StoreDocumentResult, error) {
+	result, err := m.writeBatch(ctx, []*batchDocument{{docID: docID, pending: entryList}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document ID '%s': %v", docID, err)
+	}
+
+	log.Printf("Object Write succesfull: index(%d) - keyID(%s)", result.Documents[docID].Index, docID)
+
+	return result.Documents[docID], nil
+}
+
+// writeDocumentChanges commits the given property list as the new version of a
+// document, based on the given document details. The caller must hold the lock
+// of the document, the details being its latest version.
+func (m *Manager) writeDocumentChanges(ctx context.Context, docDetails *documentDetails, entryList doc.PropertyEntryList) (*GetDocumentResult, error) {
+	docID := docDetails.objectManifest.ObjectID
+	batchDoc := newBatchDocument(docID, docDetails, entryList, m.conf.SaltedProperties)
+
+	result, err := m.commitBatch(ctx, []*batchDocument{batchDoc})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update document ID '%s': %w", docID, err)
+	}
+
+	log.Printf("Object Update succesfull: index(%d) - keyID(%s) - written(%d)", result.Documents[docID].Index, docID, len(batchDoc.pending))
+
+	return &GetDocumentResult{
+		ID:         docID,
+		Index:      result.Documents[docID].Index,
+		Hash:       result.Documents[docID].Hash,
+		MerkleRoot: result.Documents[docID].MerkleRoot,
+	}, nil
+}
+
+// writeBatch writes the pending properties of a list of documents, followed by
+// their new manifests, as a single batch operation. Nothing is written if any
+// of the documents is no longer at its expected version.
+func (m *Manager) writeBatch(ctx context.Context, docs []*batchDocument) (*CommitResult, error) {
+	docIDs := make([]string, len(docs))
+	for i, d := range docs {
+		docIDs[i] = d.docID
+	}
+	unlock := m.locks.lock(docIDs...)
+	defer unlock()
+
+	return m.commitBatch(ctx, docs)
+}
+
+// commitBatch writes a batch of documents, as described by writeBatch. The
+// caller must hold the locks of the documents.
+func (m *Manager) commitBatch(ctx context.Context, docs []*batchDocument) (*CommitResult, error) {
+	for _, d := range docs {
+		if d.expected == (ExpectedVersion{}) {
+			continue
+		}
+		docDetails, err := m.getDocumentDetails(ctx, d.docID)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.expected.check(docDetails); err != nil {
+			return nil, err
+		}
+	}
+
+	// Documents not based on a previous version replace the latest one, if
+	// any, on which their manifest is based.
+	for _, d := range docs {
+		if d.previousIndex != 0 {
+			continue
+		}
+		previousIndex, err := m.latestManifestIndex(ctx, d.docID)
+		if err != nil {
+			return nil, err
+		}
+		d.previousIndex = previousIndex
+	}
+
+	ops := &immuschema.Ops{}
+
+	// Position of each pending property within the batch, per document.
+	propertyPositions := make([][]uint64, len(docs))
+	for i, d := range docs {
+		pending, err := saltValues(d.pending)
+		if err != nil {
+			return nil, err
+		}
+		d.pending = pending
+		for _, entry := range d.pending {
+			propertyPositions[i] = append(propertyPositions[i], uint64(len(ops.Operations)))
+			ops.Operations = append(ops.Operations, newKVOperation([]byte(entry.KeyURI), entry.Value))
+		}
+	}
+
+	manifests := make([]*ObjectManifest, len(docs))
+	manifestPositions := make([]uint64, len(docs))
+	for i, d := range docs {
+		sort.Sort(d.committed)
+
+		manifestPositions[i] = uint64(len(ops.Operations))
+		// The global hash depends on the indexes assigned to the properties
+		// written in the batch, unlike the content hash and the Merkle root,
+		// which only depend on their keys and values. The pending properties
+		// are written after the committed ones, in order, hence the list is
+		// in order of index, although the pending indexes are not known yet.
+		contentList := append(doc.PropertyHashList{}, d.committed...)
+		for _, entry := range d.pending {
+			contentList = append(contentList, &doc.PropertyHash{
+				Key:     entry.KeyURI,
+				Content: doc.ContentHash([]byte(entry.KeyURI), entry.Value),
+			})
+		}
+		var merkle doc.MerkleBuilder
+		for _, hash := range contentList {
+			merkle.Append(hash.Content)
+		}
+		manifests[i] = &ObjectManifest{
+			ObjectID:      d.docID,
+			Indexes:       d.committed.Indexes(),
+			ContentHash:   contentList.ContentHash(),
+			MerkleRoot:    merkle.Root(),
+			PreviousIndex: d.previousIndex,
+		}
+		for _, position := range propertyPositions[i] {
+			manifests[i].Offsets = append(manifests[i].Offsets, manifestPositions[i]-position)
+		}
+
+		manifestValue, err := m.encodeManifest(manifests[i])
+		if err != nil {
+			return nil, err
+		}
+		ops.Operations = append(ops.Operations, newKVOperation(manifestKey(d.docID), manifestValue))
+	}
+
+	index, err := m.client.ExecAllOps(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	// The batch entries are assigned consecutive indexes, the returned index
+	// being the one of the last entry.
+	firstIndex := index.Index + 1 - uint64(len(ops.Operations))
+
+	result := &CommitResult{
+		Index:     index.Index,
+		Documents: make(map[string]*StoreDocumentResult, len(docs)),
+	}
+	for i, d := range docs {
+		manifestIndex := firstIndex + manifestPositions[i]
+		if err := manifests[i].resolveIndexes(manifestIndex); err != nil {
+			return nil, err
+		}
+
+		hashList := append(doc.PropertyHashList{}, d.committed...)
+		for j, entry := range d.pending {
+			propertyIndex := firstIndex + propertyPositions[i][j]
+			hashList = append(hashList, doc.CreatePropertyHash(propertyIndex, []byte(entry.KeyURI), entry.Value))
+		}
+		sort.Sort(hashList)
+
+		result.Documents[d.docID] = &StoreDocumentResult{
+			Index:      manifestIndex,
+			Hash:       hashList.Hash(),
+			MerkleRoot: manifests[i].MerkleRoot,
+		}
+	}
+	result.Hash = result.documentsHash()
+
+	return result, nil
+}
+
+// documentsHash returns the hash of the documents' hashes of a commit result,
+// sorted by document ID.
+func (c *CommitResult) documentsHash() string {
+	docIDs := make([]string, 0, len(c.Documents))
+	for docID := range c.Documents {
+		docIDs = append(docIDs, docID)
+	}
+	sort.Strings(docIDs)
+
+	globalSum := sha256.New()
+	for _, docID := range docIDs {
+		hash, _ := hex.DecodeString(c.Documents[docID].Hash)
+		_, _ = globalSum.Write(hash)
+	}
+
+	return hex.EncodeToString(globalSum.Sum(nil))
+}
+
+// newKVOperation wraps a key-value pair as a batch operation.
+func newKVOperation(key, value []byte) *immuschema.Op {
+	return &immuschema.Op{
+		Operation: &immuschema.Op_KVs{
+			KVs: &immuschema.KeyValue{Key: key, Value: value},
+		},
+	}
+}
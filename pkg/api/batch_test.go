@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerStoreDocumentAtomic(t *testing.T) {
+	jsonPayload := []byte(`{
+		"name": "John",
+		"age": 30,
+		"cars": {"car1": "Ford", "car2": "BMW"},
+		"tags": ["tag1", "tag2"]
+	}`)
+
+	clientMock, store := newMemoryClientMock()
+	// Add some unrelated entries so that the document is not stored at index 0.
+	store.append([]byte("other/key"), []byte("value"))
+	store.append([]byte("other/key2"), []byte("value"))
+
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, uint64(9), storeResult.Index)
+	assert.Len(t, store.entries, 10)
+
+	// The stored manifest only records the offsets of the properties.
+	storedManifest := &ObjectManifest{}
+	if err := json.Unmarshal(store.entries[9].Value.Payload, storedManifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{7, 6, 5, 4, 3, 2, 1}, storedManifest.Offsets)
+	assert.Empty(t, storedManifest.Hash)
+	assert.NotEmpty(t, storedManifest.ContentHash)
+
+	details, err := manager.getDocumentDetails(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{2, 3, 4, 5, 6, 7, 8}, details.objectManifest.Indexes)
+	assert.Equal(t, storeResult.Hash, details.objectManifest.Hash)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(jsonPayload), string(getResult.Payload))
+	assert.Equal(t, storeResult.Hash, getResult.Hash)
+
+	isValid, err := manager.VerifyDocument(context.Background(), "docID", storeResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, isValid)
+
+	// Updates of a batch document write a manifest with absolute indexes.
+	updateResult, err := manager.UpdateDocument(context.Background(), "docID", "/name", json.RawMessage(`"Jane"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	details, err = manager.getDocumentDetails(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Empty(t, details.objectManifest.Offsets)
+	assert.Equal(t, updateResult.Index, details.objectManifestIndex)
+}
+
+func TestManagerStoreDocumentAtomic_Replayed(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	storeResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"amount": "100", "payee": "John"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replaying the manifest after forged properties makes its offsets point
+	// to them, whose content it does not record.
+	store.append([]byte("docID/amount/string"), []byte("999999"))
+	store.append([]byte("docID/payee/string"), []byte("John"))
+	store.append(manifestKey("docID"), store.entries[storeResult.Index].Value.Payload)
+
+	_, err = manager.GetDocument(ctx, "docID")
+	assert.EqualError(t, err, "properties of document ID 'docID' do not match its manifest at index 5")
+	_, err = manager.VerifyDocument(ctx, "docID", storeResult.Hash)
+	assert.Error(t, err)
+
+	// A batch manifest which does not record a content hash does not commit
+	// its properties either.
+	store.append(manifestKey("docID"), []byte(`{"id":"docID","indexes":null,"offsets":[3,2],"hash":""}`))
+	_, err = manager.GetDocument(ctx, "docID")
+	assert.EqualError(t, err, "properties of document ID 'docID' do not match its manifest at index 6")
+}
+
+func TestManagerStoreDocumentAtomic_Failure(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	clientMock.execAllOpsFn = func(ctx context.Context, ops *immuschema.Ops) (*immuschema.Index, error) {
+		return nil, errors.New("batch error")
+	}
+
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic),
+		client: clientMock,
+	}
+
+	_, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John"}`)))
+	assert.EqualError(t, err, "failed to store document ID 'docID': batch error")
+	assert.Empty(t, store.entries)
+
+	_, err = manager.GetDocument(context.Background(), "docID")
+	assert.Error(t, err)
+}
+
+func TestObjectManifestResolveIndexes(t *testing.T) {
+	manifest := &ObjectManifest{ObjectID: "docID", Offsets: []uint64{3, 2, 1}}
+	if err := manifest.resolveIndexes(10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{7, 8, 9}, manifest.Indexes)
+
+	manifest = &ObjectManifest{ObjectID: "docID", Offsets: []uint64{11}}
+	assert.Error(t, manifest.resolveIndexes(10))
+
+	manifest = &ObjectManifest{ObjectID: "docID", Indexes: []uint64{1, 2}}
+	if err := manifest.resolveIndexes(10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{1, 2}, manifest.Indexes)
+
+	manifest = &ObjectManifest{ObjectID: "docID", Indexes: []uint64{1, 2}, Offsets: []uint64{4, 1}}
+	if err := manifest.resolveIndexes(10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []uint64{1, 2, 6, 9}, manifest.Indexes)
+	assert.Empty(t, manifest.Offsets)
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// maxProofAttempts is the number of times proofs against a single Database
+// root are gathered while the Database keeps changing.
+const maxProofAttempts = 3
+
+// errRootChanged is returned when the Database root changed while gathering
+// proofs against it.
+var errRootChanged = errors.New("database root changed")
+
+// ProofBundle represents the evidence that the latest version of a document is
+// included, untampered, in the Database. It holds the manifest of the document,
+// every one of its properties, the index blocks of streamed documents, their
+// inclusion proofs, and the root, signed by the Database if it is configured
+// to, all proofs were built against. A bundle is self-contained: it can be
+// verified without access to the Database.
+type ProofBundle struct {
+	DocumentID  string           `json:"documentID"`
+	Manifest    *PropertyProof   `json:"manifest"`
+	IndexBlocks []*PropertyProof `json:"indexBlocks,omitempty"`
+	Properties  []*PropertyProof `json:"properties"`
+	Root        *immuschema.Root `json:"root"`
+}
+
+// ExportProofBundle returns the proof bundle of the latest version of a
+// document.
+func (m *Manager) ExportProofBundle(ctx context.Context, docID string) (*ProofBundle, error) {
+	for attempt := 0; attempt < maxProofAttempts; attempt++ {
+		bundle, err := m.exportProofBundle(ctx, docID)
+		if err != errRootChanged {
+			return bundle, err
+		}
+	}
+	return nil, fmt.Errorf("unable to export proof bundle of document ID '%s': %v", docID, errRootChanged)
+}
+
+// exportProofBundle gathers the proofs of a document against the current root
+// of the Database, failing if the root changed in the meantime.
+func (m *Manager) exportProofBundle(ctx context.Context, docID string) (*ProofBundle, error) {
+	root, err := m.client.CurrentRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &ProofBundle{DocumentID: docID, Root: root}
+	bundle.Manifest, err = m.proveEntry(ctx, docID, docDetails.objectManifestIndex, docDetails.objectManifestKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, blockIndex := range docDetails.indexBlocks {
+		proof, err := m.proveEntry(ctx, docID, blockIndex, string(indexBlockKey(docID)))
+		if err != nil {
+			return nil, err
+		}
+		bundle.IndexBlocks = append(bundle.IndexBlocks, proof)
+	}
+	for _, hash := range docDetails.propertyHashList {
+		proof, err := m.proveEntry(ctx, docID, hash.Index, hash.Key)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Properties = append(bundle.Properties, proof)
+	}
+
+	for _, proof := range bundle.proofs() {
+		if proof.Proof.At != root.GetIndex() || !bytes.Equal(proof.Root, root.GetRoot()) {
+			return nil, errRootChanged
+		}
+	}
+
+	return bundle, nil
+}
+
+// proofs returns every inclusion proof of a proof bundle.
+func (b *ProofBundle) proofs() []*PropertyProof {
+	proofs := append([]*PropertyProof{b.Manifest}, b.IndexBlocks...)
+	return append(proofs, b.Properties...)
+}
+
+// ReadProofBundle reads a proof bundle written by WriteTo.
+func ReadProofBundle(r io.Reader) (*ProofBundle, error) {
+	bundle := &ProofBundle{}
+	if err := json.NewDecoder(r).Decode(bundle); err != nil {
+		return nil, fmt.Errorf("unable to unmarshall proof bundle: %v", err)
+	}
+	return bundle, nil
+}
+
+// WriteTo writes a proof bundle as JSON.
+func (b *ProofBundle) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// BundleTrust represents what the verifier of a proof bundle trusts. A bundle
+// only proves that its entries are included in its own root, hence at least
+// one of the public key the Database signs its roots with, or the expected
+// hash of the document, is required to anchor it.
+type BundleTrust struct {
+	ServerKey   *ecdsa.PublicKey            // Key the bundle's root must be signed with, if any.
+	Hash        string                      // Expected global hash of the document, if any.
+	TrustedKeys map[string]crypto.PublicKey // Keys the manifest must be signed by, if any, indexed by key ID.
+}
+
+// Verify re-checks a proof bundle, without access to the Database, given what
+// the verifier trusts. Every entry must be included in the bundle's root, which
+// must be signed by the trusted server key if given, or else whose signature,
+// if any, must be valid. The manifest must be signed by one of the trusted
+// keys, if any, and the properties must be exactly those of the manifest, their
+// hash matching the manifest's and the expected one, if given. It returns the
+// verified manifest, its hash resolved as done when reading the document.
+func (b *ProofBundle) Verify(trust BundleTrust) (*ObjectManifest, error) {
+	if trust.ServerKey == nil && trust.Hash == "" {
+		return nil, errors.New("proof bundle requires a trusted server key or an expected hash to be verified")
+	}
+	if b.Manifest == nil || b.Root == nil {
+		return nil, errors.New("proof bundle is incomplete")
+	}
+	if trust.ServerKey != nil {
+		if err := checkRootSignedBy(b.Root, trust.ServerKey); err != nil {
+			return nil, err
+		}
+	} else if b.Root.GetSignature().GetSignature() != nil {
+		if ok, err := b.Root.CheckSignature(); err != nil || !ok {
+			return nil, errors.New("root signature failed verification")
+		}
+	}
+
+	for _, proof := range b.proofs() {
+		if proof == nil || !proof.Verify() {
+			return nil, errors.New("inclusion proof failed verification")
+		}
+		if proof.Proof.At != b.Root.GetIndex() || !bytes.Equal(proof.Root, b.Root.GetRoot()) {
+			return nil, fmt.Errorf("entry at index %d is not proven against the bundle's root", proof.Index)
+		}
+	}
+
+	if b.Manifest.Key != string(manifestKey(b.DocumentID)) {
+		return nil, fmt.Errorf("entry at index %d is not the manifest of document ID '%s'", b.Manifest.Index, b.DocumentID)
+	}
+	manifest := &ObjectManifest{}
+	if err := json.Unmarshal(b.Manifest.Value, manifest); err != nil {
+		return nil, fmt.Errorf("unable to unmarshall object manifest: %v", err)
+	}
+	if len(trust.TrustedKeys) > 0 {
+		if err := manifest.VerifySignature(trust.TrustedKeys); err != nil {
+			return nil, &SignatureError{DocID: b.DocumentID, Index: b.Manifest.Index, Err: err}
+		}
+	}
+	writtenInBatch := len(manifest.Offsets) > 0
+	if err := manifest.resolveIndexes(b.Manifest.Index); err != nil {
+		return nil, err
+	}
+	indexBlocks := make(map[uint64]*PropertyProof, len(b.IndexBlocks))
+	for _, proof := range b.IndexBlocks {
+		indexBlocks[proof.Index] = proof
+	}
+	_, err := manifest.resolveIndexBlocks(b.Manifest.Index, func(index uint64) ([]byte, []byte, error) {
+		proof, ok := indexBlocks[index]
+		if !ok {
+			return nil, nil, fmt.Errorf("proof bundle is missing the index block at index %d", index)
+		}
+		return []byte(proof.Key), proof.Value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[uint64]bool, len(manifest.Indexes))
+	for _, index := range manifest.Indexes {
+		indexes[index] = true
+	}
+	propertyHashList := doc.PropertyHashList{}
+	for _, proof := range b.Properties {
+		if !indexes[proof.Index] || !strings.HasPrefix(proof.Key, b.DocumentID+"/") {
+			return nil, fmt.Errorf("entry at index %d is not a property of document ID '%s'", proof.Index, b.DocumentID)
+		}
+		delete(indexes, proof.Index)
+		propertyHashList = append(propertyHashList, doc.CreatePropertyHash(proof.Index, []byte(proof.Key), proof.storedValue()))
+	}
+	if len(indexes) > 0 {
+		return nil, fmt.Errorf("proof bundle is missing %d properties of document ID '%s'", len(indexes), b.DocumentID)
+	}
+
+	sort.Sort(propertyHashList)
+	hash, merkleRoot := propertyHashList.Hash(), propertyHashList.MerkleRoot()
+	if writtenInBatch {
+		// Batch manifests have their global hash resolved from the properties,
+		// whose content must be the one recorded.
+		if manifest.ContentHash != propertyHashList.ContentHash() {
+			return nil, fmt.Errorf("hash of document ID '%s' does not match its manifest", b.DocumentID)
+		}
+		manifest.Hash = hash
+	}
+	if manifest.Hash != hash || (manifest.MerkleRoot != "" && manifest.MerkleRoot != merkleRoot) {
+		return nil, fmt.Errorf("hash of document ID '%s' does not match its manifest", b.DocumentID)
+	}
+	if trust.Hash != "" && trust.Hash != hash {
+		return nil, fmt.Errorf("hash of document ID '%s' does not match the expected hash", b.DocumentID)
+	}
+
+	return manifest, nil
+}
+
+// checkRootSignedBy ensures that a root is signed with the given key of the
+// Database, as done by ImmuDB when configured with a signing key.
+func checkRootSignedBy(root *immuschema.Root, serverKey *ecdsa.PublicKey) error {
+	signature := root.GetSignature()
+	if signature.GetSignature() == nil {
+		return errors.New("root of the proof bundle is not signed")
+	}
+	if serverKey.Curve != elliptic.P256() {
+		return fmt.Errorf("unsupported curve %s of server key", serverKey.Params().Name)
+	}
+	if !bytes.Equal(signature.GetPublicKey(), elliptic.Marshal(serverKey.Curve, serverKey.X, serverKey.Y)) {
+		return errors.New("root of the proof bundle is signed by an untrusted key")
+	}
+	if ok, err := root.CheckSignature(); err != nil || !ok {
+		return errors.New("root signature failed verification")
+	}
+	return nil
+}
+
+// Payload returns the JSON payload of the document held by a verified proof
+// bundle.
+func (b *ProofBundle) Payload() ([]byte, error) {
+	var propertyList doc.PropertyEntryList
+	for _, proof := range b.Properties {
+		propertyList = append(propertyList, doc.PropertyEntry{KeyURI: proof.Key, Value: proof.Value})
+	}
+	return doc.JSONCodec.Encode(doc.PropertyListToRaw(propertyList))
+}
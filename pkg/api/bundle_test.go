@@ -0,0 +1,228 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/immudb/pkg/signer"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerExportProofBundle(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30, "cars": {"car1": "Ford", "car2": "BMW"}}`)
+
+	for _, mode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		clientMock, _ := newMemoryClientMock()
+		manager := Manager{
+			conf:   *DefaultConfig().WithNumberWorkers(1).WithWriteMode(mode),
+			client: clientMock,
+		}
+
+		storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		bundle, err := manager.ExportProofBundle(context.Background(), "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, 4, len(bundle.Properties))
+
+		// The bundle is verified on its own, once written and read back.
+		var buf bytes.Buffer
+		if _, err := bundle.WriteTo(&buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		readBundle, err := ReadProofBundle(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		trust := BundleTrust{Hash: storeResult.Hash}
+		manifest, err := readBundle.Verify(trust)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, storeResult.Hash, manifest.Hash)
+
+		// A bundle is only consistent with itself, unless anchored.
+		_, err = readBundle.Verify(BundleTrust{})
+		assert.EqualError(t, err, "proof bundle requires a trusted server key or an expected hash to be verified")
+		_, err = readBundle.Verify(BundleTrust{Hash: "otherHash"})
+		assert.EqualError(t, err, "hash of document ID 'docID' does not match the expected hash")
+		payload, err := readBundle.Payload()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.JSONEq(t, string(jsonPayload), string(payload))
+
+		// Any tampering with the bundle is detected.
+		tampered := *bundle
+		tampered.Properties = bundle.Properties[1:]
+		_, err = tampered.Verify(trust)
+		assert.EqualError(t, err, "proof bundle is missing 1 properties of document ID 'docID'")
+
+		tampered = *bundle
+		property := *bundle.Properties[0]
+		property.Value = []byte("Fiat")
+		tampered.Properties = append([]*PropertyProof{&property}, bundle.Properties[1:]...)
+		_, err = tampered.Verify(trust)
+		assert.EqualError(t, err, "inclusion proof failed verification")
+
+		tampered = *bundle
+		tampered.DocumentID = "otherID"
+		_, err = tampered.Verify(trust)
+		assert.EqualError(t, err, "entry at index 4 is not the manifest of document ID 'otherID'")
+
+		tampered = *bundle
+		tampered.Root = immuschema.NewRoot()
+		tampered.Root.SetIndex(bundle.Root.GetIndex() + 1)
+		tampered.Root.SetRoot(bundle.Root.GetRoot())
+		_, err = tampered.Verify(trust)
+		assert.EqualError(t, err, "entry at index 4 is not proven against the bundle's root")
+
+		// A manifest proven against another root is rejected.
+		if _, err := manager.UpdateDocument(context.Background(), "docID", "/name", []byte(`"Jane"`)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tampered = *bundle
+		tampered.Manifest, err = manager.ProveManifest(context.Background(), "docID", bundle.Manifest.Index)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = tampered.Verify(trust)
+		assert.EqualError(t, err, "entry at index 4 is not proven against the bundle's root")
+	}
+}
+
+func TestProofBundleVerify_SignedRoot(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John"}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Sign the root as done by ImmuDB when configured with a signing key.
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	currentRootFn := clientMock.currentRootFn
+	clientMock.currentRootFn = func(ctx context.Context) (*immuschema.Root, error) {
+		root, _ := currentRootFn(ctx)
+		payload, _ := proto.Marshal(root.Payload)
+		signature, publicKey, err := signer.NewSignerFromPKey(rand.Reader, privateKey).Sign(payload)
+		root.Signature = &immuschema.Signature{Signature: signature, PublicKey: publicKey}
+		return root, err
+	}
+
+	bundle, err := manager.ExportProofBundle(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trust := BundleTrust{ServerKey: &privateKey.PublicKey}
+	if _, err := bundle.Verify(trust); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Roots signed by any other key are rejected.
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = bundle.Verify(BundleTrust{ServerKey: &otherKey.PublicKey})
+	assert.EqualError(t, err, "root of the proof bundle is signed by an untrusted key")
+
+	unsigned := *bundle
+	unsigned.Root = proto.Clone(bundle.Root).(*immuschema.Root)
+	unsigned.Root.Signature = nil
+	_, err = unsigned.Verify(trust)
+	assert.EqualError(t, err, "root of the proof bundle is not signed")
+
+	bundle.Root.Signature.Signature[len(bundle.Root.Signature.Signature)-1] ^= 1
+	_, err = bundle.Verify(trust)
+	assert.EqualError(t, err, "root signature failed verification")
+	// Without a server key, a signature is still checked if present.
+	_, err = bundle.Verify(BundleTrust{Hash: "hash"})
+	assert.EqualError(t, err, "root signature failed verification")
+}
+
+func TestProofBundleVerify_SignedManifest(t *testing.T) {
+	_, signer, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, mode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		clientMock, _ := newMemoryClientMock()
+		manager := Manager{
+			conf:   *DefaultConfig().WithNumberWorkers(1).WithWriteMode(mode).WithSigner("key1", signer),
+			client: clientMock,
+		}
+
+		storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John"}`)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		bundle, err := manager.ExportProofBundle(context.Background(), "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		trust := BundleTrust{
+			Hash:        storeResult.Hash,
+			TrustedKeys: map[string]crypto.PublicKey{"key1": signer.Public()},
+		}
+		if _, err := bundle.Verify(trust); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		trust.TrustedKeys = map[string]crypto.PublicKey{"key1": otherKey.Public()}
+		_, err = bundle.Verify(trust)
+		var signatureErr *SignatureError
+		assert.True(t, errors.As(err, &signatureErr))
+	}
+}
+
+func TestManagerExportProofBundle_RootChanged(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John"}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Every export races with another write.
+	currentRootFn := clientMock.currentRootFn
+	clientMock.currentRootFn = func(ctx context.Context) (*immuschema.Root, error) {
+		root, err := currentRootFn(ctx)
+		store.append([]byte("otherID/name/string"), []byte("Jane"))
+		return root, err
+	}
+
+	_, err := manager.ExportProofBundle(context.Background(), "docID")
+	assert.EqualError(t, err, "unable to export proof bundle of document ID 'docID': database root changed")
+
+	_, err = manager.ExportProofBundle(context.Background(), "missingID")
+	assert.EqualError(t, err, (&NotFoundError{DocID: "missingID"}).Error())
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// ExpectedVersion identifies the version of a document on which an update is
+// based. The zero value matches any version.
+type ExpectedVersion struct {
+	Index *uint64 // Index of the expected manifest, if not nil.
+	Hash  string  // Global hash of the expected version, if not empty.
+}
+
+// check ensures that the given document details match the expected version.
+func (e ExpectedVersion) check(d *documentDetails) error {
+	actualIndex, actualHash := d.objectManifestIndex, d.hash()
+	if (e.Index != nil && *e.Index != actualIndex) || (e.Hash != "" && e.Hash != actualHash) {
+		return &ConflictError{
+			DocID:       d.objectManifest.ObjectID,
+			Expected:    e,
+			ActualIndex: actualIndex,
+			ActualHash:  actualHash,
+		}
+	}
+	return nil
+}
+
+// ConflictError is returned when an update is not based on the latest version
+// of a document, meaning the document was modified in the meantime.
+type ConflictError struct {
+	DocID       string
+	Expected    ExpectedVersion
+	ActualIndex uint64
+	ActualHash  string
+}
+
+func (e *ConflictError) Error() string {
+	expectedIndex := "any"
+	if e.Expected.Index != nil {
+		expectedIndex = strconv.FormatUint(*e.Expected.Index, 10)
+	}
+	return fmt.Sprintf("document docID=%s was modified: expected index=%s hash=%s, latest is index=%d hash=%s",
+		e.DocID, expectedIndex, e.Expected.Hash, e.ActualIndex, e.ActualHash)
+}
+
+// UpdateDocumentIf allows the update of a given property of a document, only if
+// the latest version of the document is the expected one. Otherwise, a
+// ConflictError is returned and nothing is written.
+func (m *Manager) UpdateDocumentIf(ctx context.Context, docID string, expected ExpectedVersion, path string, value json.RawMessage) (*GetDocumentResult, error) {
+	return m.updateDocument(ctx, docID, expected, path, value)
+}
+
+// UpdateFunc computes the update of a property, provided the current version
+// of a document.
+type UpdateFunc func(current *GetDocumentResult) (path string, value json.RawMessage, err error)
+
+// RetryUpdateDocument applies a change to the latest version of a document. If
+// the document is modified while doing so, the change is re-applied to the new
+// latest version, up to the given number of attempts.
+func (m *Manager) RetryUpdateDocument(ctx context.Context, docID string, attempts int, fn UpdateFunc) (*GetDocumentResult, error) {
+	if attempts <= 0 {
+		return nil, fmt.Errorf("invalid number of attempts %d", attempts)
+	}
+
+	var conflictErr error
+	for i := 0; i < attempts; i++ {
+		current, err := m.GetDocument(ctx, docID)
+		if err != nil {
+			return nil, err
+		}
+
+		path, value, err := fn(current)
+		if err != nil {
+			return nil, err
+		}
+
+		expected := ExpectedVersion{Index: &current.Index, Hash: current.Hash}
+		result, err := m.UpdateDocumentIf(ctx, docID, expected, path, value)
+		if errors.As(err, new(*ConflictError)) {
+			conflictErr = err
+			continue
+		}
+
+		return result, err
+	}
+
+	return nil, fmt.Errorf("failed to update document docID=%s after %d attempts: %w", docID, attempts, conflictErr)
+}
+
+// documentLocks serializes, per document, the writes of document manifests,
+// so that checking the latest version of a document and writing a new one is
+// done atomically within a Manager. ImmuDB does not support conditional
+// writes, hence writers using other Managers are not coordinated.
+type documentLocks struct {
+	mu    sync.Mutex
+	locks map[string]*documentLock
+}
+
+// documentLock represents the lock of a single document, and the number of
+// goroutines holding or waiting for it.
+type documentLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lock acquires the locks of the given documents, returning the function that
+// releases them.
+func (l *documentLocks) lock(docIDs ...string) func() {
+	// Sorted, unique document IDs, so that locks are always acquired in the same
+	// order.
+	ids := make([]string, 0, len(docIDs))
+	seen := make(map[string]bool, len(docIDs))
+	for _, docID := range docIDs {
+		if !seen[docID] {
+			seen[docID] = true
+			ids = append(ids, docID)
+		}
+	}
+	sort.Strings(ids)
+
+	acquired := make([]*documentLock, 0, len(ids))
+	for _, docID := range ids {
+		l.mu.Lock()
+		if l.locks == nil {
+			l.locks = make(map[string]*documentLock)
+		}
+		dl, ok := l.locks[docID]
+		if !ok {
+			dl = &documentLock{}
+			l.locks[docID] = dl
+		}
+		dl.refs++
+		l.mu.Unlock()
+
+		dl.Lock()
+		acquired = append(acquired, dl)
+	}
+
+	return func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			acquired[i].Unlock()
+
+			l.mu.Lock()
+			acquired[i].refs--
+			if acquired[i].refs == 0 {
+				delete(l.locks, ids[i])
+			}
+			l.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerUpdateDocumentIf(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John", "age": 30}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updateResult, err := manager.UpdateDocumentIf(context.Background(), "docID",
+		ExpectedVersion{Index: &storeResult.Index}, "/name", json.RawMessage(`"Jane"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The update result matches the document as read.
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, updateResult.Index, getResult.Index)
+	assert.Equal(t, updateResult.Hash, getResult.Hash)
+
+	// Updates based on a previous version are rejected, without writing anything.
+	// Index 0 is a valid index, rather than any index.
+	numEntries := len(store.entries)
+	zeroIndex := uint64(0)
+	for _, expected := range []ExpectedVersion{
+		{Index: &storeResult.Index},
+		{Index: &zeroIndex},
+		{Hash: storeResult.Hash},
+		{Index: &updateResult.Index, Hash: storeResult.Hash},
+	} {
+		_, err = manager.UpdateDocumentIf(context.Background(), "docID", expected, "/name", json.RawMessage(`"Joe"`))
+
+		var conflictErr *ConflictError
+		if assert.True(t, errors.As(err, &conflictErr)) {
+			assert.Equal(t, "docID", conflictErr.DocID)
+			assert.Equal(t, expected, conflictErr.Expected)
+			assert.Equal(t, updateResult.Index, conflictErr.ActualIndex)
+			assert.Equal(t, updateResult.Hash, conflictErr.ActualHash)
+		}
+	}
+	assert.Len(t, store.entries, numEntries)
+
+	_, err = manager.UpdateDocumentIf(context.Background(), "docID",
+		ExpectedVersion{Index: &updateResult.Index, Hash: updateResult.Hash}, "/name", json.RawMessage(`"Joe"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The new manifest records the version it is based on.
+	versions, err := manager.GetDocumentHistory(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assert.Len(t, versions, 3) {
+		assert.Equal(t, versions[1].Index, versions[2].details.objectManifest.PreviousIndex)
+	}
+}
+
+func TestManagerRetryUpdateDocument(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"count": 0}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Modify the document concurrently on the first attempt.
+	attempts := 0
+	increment := func(current *GetDocumentResult) (string, json.RawMessage, error) {
+		attempts++
+		if attempts == 1 {
+			if _, err := manager.UpdateDocument(context.Background(), "docID", "/count", countValue(10)); err != nil {
+				return "", nil, err
+			}
+		}
+		return "/count", countValue(countOf(t, current) + 1), nil
+	}
+
+	if _, err := manager.RetryUpdateDocument(context.Background(), "docID", 3, increment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 2, attempts)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, float64(11), countOf(t, getResult))
+
+	// Give up after the maximum number of attempts.
+	alwaysModified := func(current *GetDocumentResult) (string, json.RawMessage, error) {
+		if _, err := manager.UpdateDocument(context.Background(), "docID", "/count", countValue(0)); err != nil {
+			return "", nil, err
+		}
+		return "/count", countValue(1), nil
+	}
+	_, err = manager.RetryUpdateDocument(context.Background(), "docID", 2, alwaysModified)
+	assert.True(t, errors.As(err, new(*ConflictError)))
+
+	failing := func(current *GetDocumentResult) (string, json.RawMessage, error) {
+		return "", nil, errors.New("update error")
+	}
+	_, err = manager.RetryUpdateDocument(context.Background(), "docID", 2, failing)
+	assert.EqualError(t, err, "update error")
+
+	_, err = manager.RetryUpdateDocument(context.Background(), "docID", 0, increment)
+	assert.EqualError(t, err, "invalid number of attempts 0")
+}
+
+func TestManagerRetryUpdateDocument_Concurrent(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"count": 0}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	increment := func(current *GetDocumentResult) (string, json.RawMessage, error) {
+		return "/count", countValue(countOf(t, current) + 1), nil
+	}
+
+	const numUpdates = 10
+	wg := &sync.WaitGroup{}
+	wg.Add(numUpdates)
+	for i := 0; i < numUpdates; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := manager.RetryUpdateDocument(context.Background(), "docID", numUpdates, increment); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, float64(numUpdates), countOf(t, getResult))
+	assert.Empty(t, manager.locks.locks)
+}
+
+func TestTransactionCommit_Conflict(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"count": 0}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tx := manager.Begin()
+	if err := tx.Store("otherID", bytes.NewReader([]byte(`{"count": 0}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Update(context.Background(), "docID", "/count", countValue(1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The document is modified before the transaction is committed.
+	if _, err := manager.UpdateDocument(context.Background(), "docID", "/count", countValue(2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numEntries := len(store.entries)
+
+	_, err := tx.Commit(context.Background())
+	assert.True(t, errors.As(err, new(*ConflictError)))
+	assert.Len(t, store.entries, numEntries)
+}
+
+// countOf returns the count property of a document.
+func countOf(t *testing.T, result *GetDocumentResult) float64 {
+	var payload struct {
+		Count float64 `json:"count"`
+	}
+	if err := json.Unmarshal(result.Payload, &payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return payload.Count
+}
+
+// countValue returns the JSON value of a count property.
+func countValue(count float64) json.RawMessage {
+	return json.RawMessage(strconv.FormatFloat(count, 'f', -1, 64))
+}
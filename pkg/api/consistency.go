@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/codenotary/merkletree"
+)
+
+// VersionConsistencyProof represents the evidence that two versions of a
+// document belong to the same append-only history of the Database. The old
+// manifest is proven against the root of the Database right after it was
+// written, the new manifest against a later root, and the consistency proof
+// shows that the later root extends the old one, i.e. that nothing was
+// rewritten in between. It carries everything required to be re-checked on its
+// own, without access to the Database.
+type VersionConsistencyProof struct {
+	DocumentID  string                       `json:"documentID"`
+	OldManifest *PropertyProof               `json:"oldManifest"`
+	NewManifest *PropertyProof               `json:"newManifest"`
+	Consistency *immuschema.ConsistencyProof `json:"consistency"`
+}
+
+// ProveVersionConsistency returns the proof that two versions of a document,
+// given the indexes of their manifests, belong to the same history.
+func (m *Manager) ProveVersionConsistency(ctx context.Context, docID string, oldManifestIndex, newManifestIndex uint64) (*VersionConsistencyProof, error) {
+	if oldManifestIndex >= newManifestIndex {
+		return nil, fmt.Errorf("manifest index %d is not older than %d", oldManifestIndex, newManifestIndex)
+	}
+
+	for attempt := 0; attempt < maxProofAttempts; attempt++ {
+		proof, err := m.proveVersionConsistency(ctx, docID, oldManifestIndex, newManifestIndex)
+		if err != errRootChanged {
+			return proof, err
+		}
+	}
+	return nil, fmt.Errorf("unable to prove consistency of document ID '%s': %v", docID, errRootChanged)
+}
+
+// proveVersionConsistency gathers the proofs of two versions of a document
+// against the current root of the Database, failing if the root changed in the
+// meantime.
+func (m *Manager) proveVersionConsistency(ctx context.Context, docID string, oldManifestIndex, newManifestIndex uint64) (*VersionConsistencyProof, error) {
+	consistencyProof, err := m.client.Consistency(ctx, oldManifestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	oldProof, err := m.ProveManifest(ctx, docID, oldManifestIndex)
+	if err != nil {
+		return nil, err
+	}
+	newProof, err := m.ProveManifest(ctx, docID, newManifestIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, proof := range []*PropertyProof{oldProof, newProof} {
+		if proof.Proof.At != consistencyProof.Second || !bytes.Equal(proof.Root, consistencyProof.SecondRoot) {
+			return nil, errRootChanged
+		}
+	}
+
+	// The old manifest is proven against the root of the Database right after
+	// it was written, whose tree is the prefix of the current one ending with
+	// the manifest.
+	oldProof.Proof = prefixInclusionProof(oldProof.Proof)
+	oldProof.Root = oldProof.Proof.Root
+
+	proof := &VersionConsistencyProof{
+		DocumentID:  docID,
+		OldManifest: oldProof,
+		NewManifest: newProof,
+		Consistency: consistencyProof,
+	}
+	if err := proof.Verify(); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// prefixInclusionProof turns the inclusion proof of an entry into the
+// inclusion proof of the same entry in the tree ending with it. The nodes on
+// the left of the entry's path are the same in both trees, while the tree
+// ending with the entry has no nodes on its right.
+func prefixInclusionProof(proof *immuschema.InclusionProof) *immuschema.InclusionProof {
+	var leaf [sha256.Size]byte
+	copy(leaf[:], proof.Leaf)
+
+	root := leaf
+	var path [][]byte
+	for i, at, step := proof.Index, proof.At, 0; step < len(proof.Path); i, at, step = i/2, at/2, step+1 {
+		if i%2 == 0 && i != at {
+			continue
+		}
+		node := [sha256.Size*2 + 1]byte{merkletree.NodePrefix}
+		copy(node[1:], proof.Path[step])
+		copy(node[sha256.Size+1:], root[:])
+		root = sha256.Sum256(node[:])
+		path = append(path, proof.Path[step])
+	}
+
+	return &immuschema.InclusionProof{
+		At:    proof.Index,
+		Index: proof.Index,
+		Root:  root[:],
+		Leaf:  proof.Leaf,
+		Path:  path,
+	}
+}
+
+// Verify re-checks a version consistency proof, without access to the
+// Database. Both entries must be manifests of the document, the old one being
+// included in the old root and the new one in the new root, and the new root
+// must extend the old one.
+func (p *VersionConsistencyProof) Verify() error {
+	if p.OldManifest == nil || p.NewManifest == nil || p.Consistency == nil {
+		return errors.New("consistency proof is incomplete")
+	}
+
+	for _, proof := range []*PropertyProof{p.OldManifest, p.NewManifest} {
+		if !proof.Verify() {
+			return fmt.Errorf("inclusion proof of entry at index %d failed verification", proof.Index)
+		}
+		if proof.Key != string(manifestKey(p.DocumentID)) {
+			return fmt.Errorf("entry at index %d is not the manifest of document ID '%s'", proof.Index, p.DocumentID)
+		}
+		manifest := &ObjectManifest{}
+		if err := json.Unmarshal(proof.Value, manifest); err != nil || manifest.ObjectID != p.DocumentID {
+			return fmt.Errorf("entry at index %d is not the manifest of document ID '%s'", proof.Index, p.DocumentID)
+		}
+	}
+	if p.OldManifest.Index >= p.NewManifest.Index {
+		return fmt.Errorf("manifest index %d is not older than %d", p.OldManifest.Index, p.NewManifest.Index)
+	}
+
+	oldRoot := immuschema.NewRoot()
+	oldRoot.SetIndex(p.OldManifest.Proof.At)
+	oldRoot.SetRoot(p.OldManifest.Root)
+	if p.Consistency.Second != p.NewManifest.Proof.At || !bytes.Equal(p.Consistency.SecondRoot, p.NewManifest.Root) ||
+		!p.Consistency.Verify(*oldRoot) {
+		return errors.New("consistency proof failed verification")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/merkletree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerProveVersionConsistency(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"name": "John", "age": 30}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		otherID := fmt.Sprintf("otherID%d", i)
+		if _, err := manager.StoreDocument(ctx, otherID, bytes.NewReader([]byte(`{"name": "Jane"}`))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := manager.UpdateDocument(ctx, "docID", "/age", json.RawMessage(fmt.Sprint(31+i))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	versions, err := manager.GetDocumentHistory(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 7, len(versions))
+
+	for i := range versions {
+		for j := 0; j < i; j++ {
+			oldIndex, newIndex := versions[j].Index, versions[i].Index
+
+			proof, err := manager.ProveVersionConsistency(ctx, "docID", oldIndex, newIndex)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The old root is the one of the Database right after the old
+			// manifest was written.
+			tree := merkletree.NewMemStore()
+			for _, entry := range store.entries[:oldIndex+1] {
+				leaf, _ := entry.Hash()
+				var hash [sha256.Size]byte
+				copy(hash[:], leaf)
+				merkletree.AppendHash(tree, &hash)
+			}
+			root := merkletree.Root(tree)
+			assert.Equal(t, root[:], proof.OldManifest.Root)
+			assert.Equal(t, store.root(), proof.NewManifest.Root)
+
+			// The proof is verified on its own, once serialized.
+			data, err := json.Marshal(proof)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			readProof := &VersionConsistencyProof{}
+			if err := json.Unmarshal(data, readProof); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.NoError(t, readProof.Verify())
+		}
+	}
+
+	proof, err := manager.ProveVersionConsistency(ctx, "docID", versions[0].Index, versions[len(versions)-1].Index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Any tampering with the proof is detected.
+	tampered := *proof
+	tampered.OldManifest, tampered.NewManifest = proof.NewManifest, proof.OldManifest
+	assert.EqualError(t, tampered.Verify(), fmt.Sprintf("manifest index %d is not older than %d", proof.NewManifest.Index, proof.OldManifest.Index))
+
+	tampered = *proof
+	oldManifest := *proof.OldManifest
+	oldManifest.Root = proof.NewManifest.Root
+	tampered.OldManifest = &oldManifest
+	assert.EqualError(t, tampered.Verify(), fmt.Sprintf("inclusion proof of entry at index %d failed verification", oldManifest.Index))
+
+	tampered = *proof
+	tampered.NewManifest, err = manager.ProveManifest(ctx, "otherID5", versions[len(versions)-1].Index-2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.EqualError(t, tampered.Verify(), fmt.Sprintf("entry at index %d is not the manifest of document ID 'docID'", versions[len(versions)-1].Index-2))
+
+	tampered = *proof
+	consistency := *proof.Consistency
+	consistency.Path = consistency.Path[1:]
+	tampered.Consistency = &consistency
+	assert.EqualError(t, tampered.Verify(), "consistency proof failed verification")
+
+	_, err = manager.ProveVersionConsistency(ctx, "docID", versions[0].Index, versions[0].Index)
+	assert.EqualError(t, err, fmt.Sprintf("manifest index %d is not older than %d", versions[0].Index, versions[0].Index))
+
+	_, err = manager.ProveVersionConsistency(ctx, "docID", 0, versions[len(versions)-1].Index)
+	assert.EqualError(t, err, "entry at index 0 has key 'docID/age/float64', expected 'manifest/docID'")
+}
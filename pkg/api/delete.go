@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+)
+
+// DeleteDocument logically deletes a document, writing a tombstone manifest that
+// records the hash of the deleted version. The document can no longer be read,
+// while its history, and the verification of its earlier versions, still work.
+func (m *Manager) DeleteDocument(ctx context.Context, docID string) (*GetDocumentResult, error) {
+	unlock := m.locks.lock(docID)
+	defer unlock()
+
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstone := &ObjectManifest{
+		ObjectID:      docID,
+		Indexes:       []uint64{},
+		Deleted:       true,
+		PreviousIndex: docDetails.objectManifestIndex,
+		PreviousHash:  docDetails.hash(),
+	}
+
+	tombstoneValue, err := m.encodeManifest(tombstone)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstoneKey := manifestKey(docID)
+	index, err := m.client.SafeSet(ctx, tombstoneKey, tombstoneValue)
+	if err != nil {
+		return nil, fmt.Errorf("unable to store manifes of object '%s': %v", docID, err)
+	}
+
+	log.Printf("Object Delete succesfull: index(%d) - keyID(%s)", index.Index, docID)
+
+	tombstoneHash := doc.CreatePropertyHash(index.Index, tombstoneKey, tombstoneValue)
+
+	return &GetDocumentResult{
+		ID:    docID,
+		Index: index.Index,
+		Hash:  hex.EncodeToString(tombstoneHash.Hash),
+	}, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerDeleteDocument(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deleteResult, err := manager.DeleteDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "docID", deleteResult.ID)
+	assert.Equal(t, storeResult.Index+1, deleteResult.Index)
+	assert.NotEmpty(t, deleteResult.Hash)
+
+	// The document can no longer be read, updated or deleted.
+	var notFoundErr *NotFoundError
+	_, err = manager.GetDocument(context.Background(), "docID")
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.EqualError(t, err, "document docID=docID not found")
+
+	_, err = manager.UpdateDocument(context.Background(), "docID", "/name", json.RawMessage(`"Jane"`))
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = manager.DeleteDocument(context.Background(), "docID")
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = manager.GetDocumentAt(context.Background(), "docID", deleteResult.Index)
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	// The earlier version can still be read and verified.
+	getResult, err := manager.GetDocumentAt(context.Background(), "docID", storeResult.Index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(jsonPayload), string(getResult.Payload))
+	assert.Equal(t, storeResult.Hash, getResult.Hash)
+
+	versions, err := manager.GetDocumentHistory(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if assert.Len(t, versions, 2) {
+		assert.False(t, versions[0].Deleted)
+		assert.Equal(t, storeResult.Hash, versions[0].Hash)
+		assert.True(t, versions[1].Deleted)
+		assert.Equal(t, deleteResult.Index, versions[1].Index)
+		assert.Equal(t, deleteResult.Hash, versions[1].Hash)
+		assert.Empty(t, versions[1].Indexes)
+
+		_, err = versions[1].Payload()
+		assert.True(t, errors.As(err, &notFoundErr))
+	}
+
+	// The deletion is provable, and records the deleted version.
+	proof, err := manager.ProveManifest(context.Background(), "docID", deleteResult.Index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, proof.Verify())
+	assert.Equal(t, deleteResult.Hash, hex.EncodeToString(proof.Digest))
+	assert.Contains(t, string(proof.Value), `"deleted":true`)
+	assert.Contains(t, string(proof.Value), `"previousHash":"`+storeResult.Hash+`"`)
+
+	// The document can be stored again.
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	getResult, err = manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(jsonPayload), string(getResult.Payload))
+
+	_, err = manager.DeleteDocument(context.Background(), "unknown")
+	assert.True(t, errors.As(err, &notFoundErr))
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// DocumentVersion represents a given version of a document, as described by
+// one of the manifests written for said document.
+type DocumentVersion struct {
+	ID        string
+	Index     uint64    // Index of the manifest DB entry.
+	Hash      string    // Global hash of the document version.
+	Indexes   []uint64  // Indexes of the properties of the document version.
+	Timestamp time.Time // Time at which the manifest was written.
+	Deleted   bool      // Whether the version is the tombstone of a deleted document.
+
+	details *documentDetails
+}
+
+// Payload reconstructs the raw JSON document of the version, unless the version
+// is a tombstone.
+func (v *DocumentVersion) Payload() ([]byte, error) {
+	return v.details.payload()
+}
+
+// GetDocumentHistory returns every version of a given document, sorted from
+// the oldest to the most recent one.
+func (m *Manager) GetDocumentHistory(ctx context.Context, docID string) ([]*DocumentVersion, error) {
+	items, err := m.client.History(ctx, &immuschema.HistoryOptions{Key: manifestKey(docID)})
+	if err != nil {
+		return nil, err
+	}
+	if len(items.GetItems()) == 0 {
+		return nil, &NotFoundError{DocID: docID}
+	}
+
+	versions := make([]*DocumentVersion, 0, len(items.Items))
+	for _, item := range items.Items {
+		docDetails, err := m.loadDocumentDetails(ctx, item.Index, item.Key, item.Value.GetPayload())
+		if err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, &DocumentVersion{
+			ID:        docID,
+			Index:     item.Index,
+			Hash:      docDetails.hash(),
+			Indexes:   docDetails.propertyHashList.Indexes(),
+			Timestamp: time.Unix(int64(item.Value.GetTimestamp()), 0),
+			Deleted:   docDetails.objectManifest.Deleted,
+			details:   docDetails,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Index < versions[j].Index
+	})
+
+	// Every version must be based on the one before it, so that no earlier
+	// manifest is replayed in the history.
+	if len(m.conf.TrustedKeys) > 0 {
+		var previousIndex uint64
+		for _, version := range versions {
+			if err := checkPreviousIndex(version.details.objectManifest, version.Index, previousIndex); err != nil {
+				return nil, err
+			}
+			previousIndex = version.Index
+		}
+	}
+
+	return versions, nil
+}
+
+// GetDocumentAt allows the extraction of a given version of a document,
+// provided the index of the manifest describing said version.
+func (m *Manager) GetDocumentAt(ctx context.Context, docID string, manifestIndex uint64) (*GetDocumentResult, error) {
+	docManifestKey := manifestKey(docID)
+
+	log.Printf("Reading object objectManifest: DocumentID(%s) - Index(%d)", docManifestKey, manifestIndex)
+	docManifestItem, err := m.client.RawBySafeIndex(ctx, manifestIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !docManifestItem.Verified {
+		return nil, fmt.Errorf("manifest at index %d failed verification", manifestIndex)
+	}
+	if !bytes.Equal(docManifestItem.Key, docManifestKey) {
+		return nil, fmt.Errorf("entry at index %d is not a manifest of document docID=%s", manifestIndex, docID)
+	}
+
+	item := &immuschema.Item{Key: docManifestItem.Key, Value: docManifestItem.Value, Index: manifestIndex}
+	structuredItem, err := item.ToSItem()
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshall entry at index %d: %v", manifestIndex, err)
+	}
+
+	docDetails, err := m.loadDocumentDetails(ctx, manifestIndex, structuredItem.Key, structuredItem.Value.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := docDetails.payload()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Object Read succesfull: index(%d) - keyID(%s)", manifestIndex, docManifestKey)
+
+	return &GetDocumentResult{
+		ID:         docID,
+		Payload:    payload,
+		Index:      manifestIndex,
+		Hash:       docDetails.hash(),
+		MerkleRoot: docDetails.objectManifest.MerkleRoot,
+	}, nil
+}
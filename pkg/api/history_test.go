@@ -0,0 +1,129 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	immuclient "github.com/codenotary/immudb/pkg/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerGetDocumentHistory(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updateResult1, err := manager.UpdateDocument(context.Background(), "docID", "/name", json.RawMessage(`"Jane"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The next version is written in a batch.
+	tx := manager.Begin()
+	if err := tx.Update(context.Background(), "docID", "/name", json.RawMessage(`"Joe"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	commitResult, err := tx.Commit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	versions, err := manager.GetDocumentHistory(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !assert.Len(t, versions, 3) {
+		return
+	}
+
+	expVersions := []struct {
+		index   uint64
+		payload []byte
+	}{
+		{index: storeResult.Index, payload: jsonPayload},
+		{index: updateResult1.Index, payload: []byte(`{"name": "Jane", "age": 30}`)},
+		{index: commitResult.Documents["docID"].Index, payload: []byte(`{"name": "Joe", "age": 30}`)},
+	}
+	for i, expVersion := range expVersions {
+		version := versions[i]
+		assert.Equal(t, "docID", version.ID)
+		assert.Equal(t, expVersion.index, version.Index)
+		assert.Equal(t, int64(store.entries[version.Index].Value.Timestamp), version.Timestamp.Unix())
+
+		payload, err := version.Payload()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.JSONEq(t, string(expVersion.payload), string(payload))
+	}
+	assert.Equal(t, storeResult.Hash, versions[0].Hash)
+	assert.Equal(t, commitResult.Documents["docID"].Hash, versions[2].Hash)
+
+	// The age property is shared by every version.
+	assert.Equal(t, versions[0].Indexes[0], versions[1].Indexes[0])
+	assert.Equal(t, versions[0].Indexes[0], versions[2].Indexes[0])
+
+	_, err = manager.GetDocumentHistory(context.Background(), "unknown")
+	assert.EqualError(t, err, "document docID=unknown not found")
+}
+
+func TestManagerGetDocumentAt(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithWriteMode(WriteModeAtomic),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.StoreDocument(context.Background(), "otherID", bytes.NewReader(jsonPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	updateResult, err := manager.UpdateDocument(context.Background(), "docID", "/name", json.RawMessage(`"Jane"`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := manager.GetDocumentAt(context.Background(), "docID", storeResult.Index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "docID", result.ID)
+	assert.Equal(t, storeResult.Index, result.Index)
+	assert.Equal(t, storeResult.Hash, result.Hash)
+	assert.JSONEq(t, string(jsonPayload), string(result.Payload))
+
+	result, err = manager.GetDocumentAt(context.Background(), "docID", updateResult.Index)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name": "Jane", "age": 30}`, string(result.Payload))
+
+	// The index of a property, or the manifest of another document, is rejected.
+	_, err = manager.GetDocumentAt(context.Background(), "docID", storeResult.Index-1)
+	assert.EqualError(t, err, "entry at index 1 is not a manifest of document docID=docID")
+
+	_, err = manager.GetDocumentAt(context.Background(), "docID", storeResult.Index+3)
+	assert.EqualError(t, err, "entry at index 5 is not a manifest of document docID=docID")
+
+	// Unverified entries are rejected.
+	clientMock.rawBySafeIndexFn = func(ctx context.Context, index uint64) (*immuclient.VerifiedItem, error) {
+		return &immuclient.VerifiedItem{Key: []byte("manifest/docID"), Index: index}, nil
+	}
+	_, err = manager.GetDocumentAt(context.Background(), "docID", storeResult.Index)
+	assert.EqualError(t, err, "manifest at index 2 failed verification")
+}
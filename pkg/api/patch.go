@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+)
+
+// PatchDocument applies a JSON Patch (RFC 6902) to the latest version of a
+// document. The resulting property writes and removals are committed, along
+// with the new manifest, as a single new version of the document.
+func (m *Manager) PatchDocument(ctx context.Context, docID string, patch io.Reader) (*GetDocumentResult, error) {
+	unlock := m.locks.lock(docID)
+	defer unlock()
+
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	patchedObject, err := doc.ApplyPatch(doc.PropertyListToRaw(docDetails.propertyEntryList), patch, m.docOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to patch document docID=%s: %v", docID, err)
+	}
+
+	return m.writeDocumentChanges(ctx, docDetails, doc.ObjectToPropertyList(docID, patchedObject))
+}
+
+// MergeDocument applies a JSON Merge Patch (RFC 7386) to the latest version of
+// a document. Only the new or changed properties are written, the properties
+// set to null are dropped, and a new manifest is committed along with them.
+func (m *Manager) MergeDocument(ctx context.Context, docID string, patch io.Reader) (*GetDocumentResult, error) {
+	unlock := m.locks.lock(docID)
+	defer unlock()
+
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedObject, err := doc.ApplyMergePatch(doc.PropertyListToRaw(docDetails.propertyEntryList), patch, m.docOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge document docID=%s: %v", docID, err)
+	}
+
+	return m.writeDocumentChanges(ctx, docDetails, doc.ObjectToPropertyList(docID, mergedObject))
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerPatchDocument(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`{"name": "John", "age": 30, "address": {"city": "Lisbon", "zip": "1000"}}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numEntries := len(store.entries)
+
+	patch := `[
+		{"op": "test", "path": "/name", "value": "John"},
+		{"op": "replace", "path": "/name", "value": "Jane"},
+		{"op": "remove", "path": "/age"},
+		{"op": "add", "path": "/tags", "value": ["a", "b"]},
+		{"op": "move", "from": "/address/zip", "path": "/zip"}
+	]`
+	patchResult, err := manager.PatchDocument(context.Background(), "docID", bytes.NewReader([]byte(patch)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the changed properties are written, along with a single manifest.
+	assert.Len(t, store.entries, numEntries+6)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name": "Jane", "tags": ["a", "b"], "address": {"city": "Lisbon"}, "zip": "1000"}`, string(getResult.Payload))
+	assert.Equal(t, patchResult.Index, getResult.Index)
+	assert.Equal(t, patchResult.Hash, getResult.Hash)
+
+	verified, err := manager.VerifyDocument(context.Background(), "docID", patchResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, verified)
+
+	// The new manifest records the version it is based on.
+	docDetails, err := manager.getDocumentDetails(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, storeResult.Index, docDetails.objectManifest.PreviousIndex)
+
+	// A failed patch writes nothing.
+	numEntries = len(store.entries)
+	_, err = manager.PatchDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`[{"op": "remove", "path": "/zip"}, {"op": "test", "path": "/name", "value": "John"}]`)))
+	assert.EqualError(t, err, "unable to patch document docID=docID: patch operation #1 (test /name) failed: value at '/name' does not match")
+	assert.Len(t, store.entries, numEntries)
+
+	_, err = manager.PatchDocument(context.Background(), "unknownID", bytes.NewReader([]byte(`[]`)))
+	assert.True(t, errors.As(err, new(*NotFoundError)))
+}
+
+func TestManagerMergeDocument(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`{"name": "John", "age": 30, "address": {"city": "Lisbon", "zip": "1000"}}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numEntries := len(store.entries)
+
+	mergeResult, err := manager.MergeDocument(context.Background(), "docID",
+		bytes.NewReader([]byte(`{"name": "John", "age": 31, "address": {"zip": null}, "email": "john@example.com"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the changed and new properties are written, along with a single manifest.
+	assert.Len(t, store.entries, numEntries+3)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name": "John", "age": 31, "address": {"city": "Lisbon"}, "email": "john@example.com"}`, string(getResult.Payload))
+	assert.Equal(t, mergeResult.Index, getResult.Index)
+	assert.Equal(t, mergeResult.Hash, getResult.Hash)
+
+	verified, err := manager.VerifyDocument(context.Background(), "docID", mergeResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, verified)
+
+	numEntries = len(store.entries)
+	_, err = manager.MergeDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": `)))
+	assert.EqualError(t, err, "unable to merge document docID=docID: unable to unmarshall patch: unexpected EOF")
+	assert.Len(t, store.entries, numEntries)
+}
+
+func TestManagerPatchDocument_NullDocument(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`null`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The patch applies to the null document itself, rather than to an empty object.
+	_, err := manager.PatchDocument(ctx, "docID", bytes.NewReader([]byte(`[{"op": "test", "path": "", "value": {}}]`)))
+	assert.EqualError(t, err, "unable to patch document docID=docID: patch operation #0 (test ) failed: value at '' does not match")
+	_, err = manager.PatchDocument(ctx, "docID", bytes.NewReader([]byte(`[{"op": "add", "path": "/name", "value": "John"}]`)))
+	assert.Error(t, err)
+
+	if _, err := manager.PatchDocument(ctx, "docID", bytes.NewReader([]byte(`[
+		{"op": "test", "path": "", "value": null},
+		{"op": "replace", "path": "", "value": ["John"]}
+	]`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	getResult, err := manager.GetDocument(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `["John"]`, string(getResult.Payload))
+}
+
+func TestManagerPatchDocument_ArrayChanges(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	list := make([]int, 1000)
+	for i := range list {
+		list[i] = i
+	}
+	payload, err := json.Marshal(map[string]interface{}{"list": list})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(payload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		patch      string
+		expWritten int
+	}{
+		// The new element and the array length.
+		{patch: `[{"op": "add", "path": "/list/-", "value": 1000}]`, expWritten: 2},
+		// The array length only.
+		{patch: `[{"op": "remove", "path": "/list/1000"}]`, expWritten: 1},
+		// The replaced element only.
+		{patch: `[{"op": "replace", "path": "/list/500", "value": -1}]`, expWritten: 1},
+	}
+
+	for _, test := range tests {
+		numEntries := len(store.entries)
+
+		if _, err := manager.PatchDocument(context.Background(), "docID", bytes.NewReader([]byte(test.patch))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// Along with the new manifest.
+		assert.Len(t, store.entries, numEntries+test.expWritten+1)
+	}
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list[500] = -1
+	payload, err = json.Marshal(map[string]interface{}{"list": list})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(payload), string(getResult.Payload))
+}
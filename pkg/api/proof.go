@@ -0,0 +1,287 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	immuclient "github.com/codenotary/immudb/pkg/client"
+)
+
+// PropertyProof represents the evidence that a single property of a document
+// is included, untampered, in the Database. It carries everything required to
+// re-check the property on its own, without access to the Database.
+type PropertyProof struct {
+	DocumentID string
+	Key        string                     // Full key of the property.
+	Value      []byte                     // Value of the property.
+	Salt       []byte                     // Salt of the property, if salted.
+	RawValue   []byte                     // Value as stored in the Database, including its timestamp.
+	Index      uint64                     // Index of the property DB entry.
+	Digest     []byte                     // Leaf digest of the property, as used in the document hash.
+	Proof      *immuschema.InclusionProof // Inclusion proof of the property DB entry.
+	Root       []byte                     // Root against which the inclusion proof was checked.
+}
+
+// Verify re-checks a property proof, returning True if the property value is
+// the one stored in the Database and is included in the proof's root, and
+// False otherwise. The proof's root is carried by the proof itself, hence
+// Verify is only as trustworthy as that root: callers must make sure it is a
+// root of the Database they trust, as done by VerifyAgainst.
+func (p *PropertyProof) Verify() bool {
+	if p == nil || p.Proof == nil || !bytes.Equal(p.Proof.Root, p.Root) {
+		return false
+	}
+
+	// The key, which is part of the digest, tells whether the property is
+	// salted, hence where its value starts.
+	saltSize := 0
+	if doc.IsSaltedKey(p.Key) {
+		saltSize = doc.SaltSize
+	}
+	if len(p.Salt) != saltSize {
+		return false
+	}
+
+	item := &immuschema.Item{Key: []byte(p.Key), Value: p.RawValue, Index: p.Index}
+	structuredItem, err := item.ToSItem()
+	if err != nil || !bytes.Equal(structuredItem.Value.Payload, p.storedValue()) {
+		return false
+	}
+
+	propertyHash := doc.CreatePropertyHash(p.Index, []byte(p.Key), p.storedValue())
+	if !bytes.Equal(propertyHash.Hash, p.Digest) {
+		return false
+	}
+
+	return p.Proof.Verify(p.Index, item.Hash())
+}
+
+// VerifyAgainst re-checks a property proof as done by Verify, requiring in
+// addition its root to be the given one, e.g. a root verified by the caller's
+// own ImmuDB client or signed by the Database.
+func (p *PropertyProof) VerifyAgainst(root *immuschema.Root) bool {
+	if p == nil || p.Proof == nil || root == nil {
+		return false
+	}
+	if p.Proof.At != root.GetIndex() || !bytes.Equal(p.Root, root.GetRoot()) {
+		return false
+	}
+	return p.Verify()
+}
+
+// storedValue returns the value of the property as stored in the Database,
+// prefixed by its salt if salted.
+func (p *PropertyProof) storedValue() []byte {
+	if len(p.Salt) == 0 {
+		return p.Value
+	}
+	return append(append([]byte{}, p.Salt...), p.Value...)
+}
+
+// ProveProperty returns the inclusion proof of a given property of a document.
+// The property is identified by its key path, excluding the document ID, e.g.
+// "name/string", with or without the marker of salted properties. The salt of a
+// salted property is revealed by its proof.
+func (m *Manager) ProveProperty(ctx context.Context, docID, path string) (*PropertyProof, error) {
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	propertyHash := docDetails.findProperty(path)
+	if propertyHash == nil {
+		return nil, fmt.Errorf("document docID=%s does not have key=%s", docID, path)
+	}
+
+	return m.proveEntry(ctx, docID, propertyHash.Index, propertyHash.Key)
+}
+
+// findProperty returns the hash of the property of a document identified by its
+// key path, or nil if there is none.
+func (d *documentDetails) findProperty(path string) *doc.PropertyHash {
+	propertyKey := d.objectManifest.ObjectID + "/" + path
+	saltedKey := doc.SaltedKey(propertyKey)
+	for _, hash := range d.propertyHashList {
+		if hash.Key == propertyKey || hash.Key == saltedKey {
+			return hash
+		}
+	}
+	return nil
+}
+
+// PropertyDisclosure represents the evidence that a single property belongs to
+// a committed document, disclosing neither the other properties nor their
+// hashes. The property is proven to be included in the Database, and the hash
+// of its key and value to be a leaf of the Merkle tree whose root is recorded
+// in the document's manifest.
+type PropertyDisclosure struct {
+	Property   *PropertyProof // Inclusion proof of the property DB entry.
+	AuditPath  *doc.AuditPath // Audit path of the property in the document's Merkle tree.
+	MerkleRoot string         // Merkle root recorded in the document's manifest.
+}
+
+// Verify re-checks a property disclosure against the known Merkle root of a
+// document, returning True if the property belongs to the document, and False
+// otherwise.
+func (d *PropertyDisclosure) Verify(merkleRoot string) bool {
+	if d == nil || d.MerkleRoot != merkleRoot || !d.Property.Verify() {
+		return false
+	}
+	return d.AuditPath.Verify(merkleRoot, doc.ContentHash([]byte(d.Property.Key), d.Property.storedValue()))
+}
+
+// DiscloseProperty returns the disclosure of a given property of a document,
+// identified by its key path as done by ProveProperty.
+func (m *Manager) DiscloseProperty(ctx context.Context, docID, path string) (*PropertyDisclosure, error) {
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	merkleRoot := docDetails.objectManifest.MerkleRoot
+	if merkleRoot == "" {
+		return nil, fmt.Errorf("manifest of document docID=%s does not record a Merkle root", docID)
+	}
+
+	propertyHash := docDetails.findProperty(path)
+	if propertyHash == nil {
+		return nil, fmt.Errorf("document docID=%s does not have key=%s", docID, path)
+	}
+	auditPath, err := docDetails.propertyHashList.AuditPath(propertyHash.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := m.proveEntry(ctx, docID, propertyHash.Index, propertyHash.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PropertyDisclosure{
+		Property:   proof,
+		AuditPath:  auditPath,
+		MerkleRoot: merkleRoot,
+	}, nil
+}
+
+// ProveManifest returns the inclusion proof of a given version of the manifest
+// of a document, including the tombstone written by its deletion. The proof's
+// digest is the hash returned when deleting the document.
+func (m *Manager) ProveManifest(ctx context.Context, docID string, manifestIndex uint64) (*PropertyProof, error) {
+	return m.proveEntry(ctx, docID, manifestIndex, string(manifestKey(docID)))
+}
+
+// proveEntry fetches, verified, the DB entry stored at a given index together
+// with its inclusion proof, whose root must extend the roots the Manager
+// verified before.
+func (m *Manager) proveEntry(ctx context.Context, docID string, index uint64, key string) (*PropertyProof, error) {
+	for attempt := 0; attempt < maxProofAttempts; attempt++ {
+		proof, err := m.proveEntryOnce(ctx, docID, index, key)
+		if err != errRootChanged {
+			return proof, err
+		}
+	}
+	return nil, fmt.Errorf("unable to prove entry at index %d: %v", index, errRootChanged)
+}
+
+// proveEntryOnce proves the DB entry stored at a given index against the
+// current root of the Database, failing if the root changed in the meantime.
+func (m *Manager) proveEntryOnce(ctx context.Context, docID string, index uint64, key string) (*PropertyProof, error) {
+	item, err := m.client.RawBySafeIndex(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Verified {
+		return nil, fmt.Errorf("entry at index %d failed verification", index)
+	}
+	if string(item.Key) != key {
+		return nil, fmt.Errorf("entry at index %d has key '%s', expected '%s'", index, item.Key, key)
+	}
+
+	structuredItem, err := (&immuschema.Item{Key: item.Key, Value: item.Value, Index: index}).ToSItem()
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshall entry at index %d: %v", index, err)
+	}
+	payload := structuredItem.Value.Payload
+	var salt []byte
+	if doc.IsSaltedKey(key) {
+		if salt, payload, err = doc.SplitSalt(payload); err != nil {
+			return nil, fmt.Errorf("invalid entry at index %d: %v", index, err)
+		}
+	}
+
+	inclusionProof, err := m.client.Inclusion(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &PropertyProof{
+		DocumentID: docID,
+		Key:        key,
+		Value:      payload,
+		Salt:       salt,
+		RawValue:   item.Value,
+		Index:      index,
+		Digest:     doc.CreatePropertyHash(index, item.Key, structuredItem.Value.Payload).Hash,
+		Proof:      inclusionProof,
+		Root:       inclusionProof.Root,
+	}
+	if !proof.Verify() {
+		return nil, fmt.Errorf("inclusion proof of entry at index %d failed verification", index)
+	}
+	if err := m.verifiedRoot.advance(ctx, m.client, inclusionProof.At, inclusionProof.Root); err != nil {
+		return nil, err
+	}
+
+	return proof, nil
+}
+
+// verifiedRoot holds the latest root of the Database a Manager has verified.
+// The first root is trusted as is, as done by the ImmuDB client, while any later
+// root must be proven to extend it, so that proofs handed out by a Manager are
+// never built against a rewritten history.
+type verifiedRoot struct {
+	mu   sync.Mutex
+	root *immuschema.Root
+}
+
+// advance checks that the root at a given index extends the verified root,
+// making it the verified root if it is newer.
+func (r *verifiedRoot) advance(ctx context.Context, client immuclient.ImmuClient, at uint64, root []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.root != nil {
+		switch {
+		case at < r.root.GetIndex():
+			// Proven against a root older than one verified concurrently.
+			return errRootChanged
+		case at == r.root.GetIndex():
+			if !bytes.Equal(root, r.root.GetRoot()) {
+				return fmt.Errorf("root at index %d does not match the verified root", at)
+			}
+			return nil
+		}
+
+		consistencyProof, err := client.Consistency(ctx, r.root.GetIndex())
+		if err != nil {
+			return err
+		}
+		if !consistencyProof.Verify(*r.root) {
+			return fmt.Errorf("root at index %d is not consistent with the verified root at index %d", at, r.root.GetIndex())
+		}
+		if consistencyProof.Second != at || !bytes.Equal(consistencyProof.SecondRoot, root) {
+			return errRootChanged
+		}
+	}
+
+	r.root = immuschema.NewRoot()
+	r.root.SetIndex(at)
+	r.root.SetRoot(root)
+	return nil
+}
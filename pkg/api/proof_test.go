@@ -0,0 +1,192 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerProveProperty(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30, "cars": {"car1": "Ford", "car2": "BMW"}}`)
+
+	for _, mode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		clientMock, _ := newMemoryClientMock()
+		manager := Manager{
+			conf:   *DefaultConfig().WithNumberWorkers(1).WithWriteMode(mode),
+			client: clientMock,
+		}
+
+		if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(jsonPayload)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		details, err := manager.getDocumentDetails(context.Background(), "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		proof, err := manager.ProveProperty(context.Background(), "docID", "cars/car2/string")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, "docID/cars/car2/string", proof.Key)
+		assert.Equal(t, []byte("BMW"), proof.Value)
+		assert.Equal(t, proof.Proof.Root, proof.Root)
+		assert.True(t, proof.Verify())
+
+		// The leaf digest is the one used to compute the document hash.
+		found := false
+		for _, hash := range details.propertyHashList {
+			if hash.Index == proof.Index {
+				assert.Equal(t, hash.Hash, proof.Digest)
+				found = true
+			}
+		}
+		assert.True(t, found)
+
+		// The proof is built against the current root of the Database, and
+		// against no other root.
+		root, err := clientMock.CurrentRoot(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.True(t, proof.VerifyAgainst(root))
+		otherRoot := immuschema.NewRoot()
+		otherRoot.SetIndex(root.GetIndex())
+		otherRoot.SetRoot(make([]byte, len(root.GetRoot())))
+		assert.False(t, proof.VerifyAgainst(otherRoot))
+
+		// Any tampering with the proof is detected.
+		tampered := *proof
+		tampered.Value = []byte("Fiat")
+		assert.False(t, tampered.Verify())
+
+		tampered = *proof
+		tampered.Digest = doc.CreatePropertyHash(proof.Index, []byte(proof.Key), []byte("Fiat")).Hash
+		assert.False(t, tampered.Verify())
+
+		tampered = *proof
+		tampered.Index++
+		assert.False(t, tampered.Verify())
+
+		_, err = manager.ProveProperty(context.Background(), "docID", "cars/car3/string")
+		assert.EqualError(t, err, "document docID=docID does not have key=cars/car3/string")
+	}
+}
+
+func TestManagerProveProperty_RewrittenHistory(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{conf: *DefaultConfig().WithNumberWorkers(1), client: clientMock}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", bytes.NewReader(jsonPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.ProveProperty(ctx, "docID", "name/string"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A Database serving another history, of the same size or longer, is not
+	// trusted once a root has been verified.
+	tests := []struct {
+		forgedEntries int
+		expectedErr   string
+	}{
+		{forgedEntries: 0, expectedErr: "root at index 2 does not match the verified root"},
+		{forgedEntries: 2, expectedErr: "root at index 4 is not consistent with the verified root at index 2"},
+	}
+	for _, test := range tests {
+		forkedMock, forkedStore := newMemoryClientMock()
+		for i := 0; i < test.forgedEntries; i++ {
+			forkedStore.append([]byte("forged"), []byte("forged"))
+		}
+		forked := Manager{conf: *DefaultConfig().WithNumberWorkers(1), client: forkedMock}
+		if _, err := forked.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"name": "Jane", "age": 30}`))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		manager.client = forkedMock
+		_, err := manager.ProveProperty(ctx, "docID", "name/string")
+		assert.EqualError(t, err, test.expectedErr)
+	}
+}
+
+func TestManagerDiscloseProperty(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30, "active": true, "address": {"city": "Metro City"}}`)
+
+	for _, mode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		clientMock, store := newMemoryClientMock()
+		manager := Manager{
+			conf:   *DefaultConfig().WithNumberWorkers(1).WithWriteMode(mode),
+			client: clientMock,
+		}
+
+		ctx := context.Background()
+		storeResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader(jsonPayload))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		getResult, err := manager.GetDocument(ctx, "docID")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.NotEmpty(t, storeResult.MerkleRoot)
+		assert.Equal(t, storeResult.MerkleRoot, getResult.MerkleRoot)
+
+		disclosure, err := manager.DiscloseProperty(ctx, "docID", "active/bool")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, []byte("true"), disclosure.Property.Value)
+		assert.True(t, disclosure.Verify(storeResult.MerkleRoot))
+
+		// Updates are written in a batch, whose manifest records its Merkle
+		// root as well.
+		updateResult, err := manager.UpdateDocument(ctx, "docID", "/age", []byte("31"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.NotEqual(t, storeResult.MerkleRoot, updateResult.MerkleRoot)
+		recordedManifest := &ObjectManifest{}
+		if err := json.Unmarshal(store.entries[updateResult.Index].Value.Payload, recordedManifest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.NotEmpty(t, recordedManifest.Offsets)
+		assert.Equal(t, updateResult.MerkleRoot, recordedManifest.MerkleRoot)
+		disclosure, err = manager.DiscloseProperty(ctx, "docID", "age/float64")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.False(t, disclosure.Verify(storeResult.MerkleRoot))
+		assert.True(t, disclosure.Verify(updateResult.MerkleRoot))
+
+		// Any tampering with the disclosure is detected.
+		tampered := *disclosure
+		property := *disclosure.Property
+		property.Value = doc.Float64ToBinary(30)
+		property.Digest = doc.CreatePropertyHash(property.Index, []byte(property.Key), property.Value).Hash
+		tampered.Property = &property
+		assert.False(t, tampered.Verify(updateResult.MerkleRoot))
+
+		tampered = *disclosure
+		auditPath := *disclosure.AuditPath
+		auditPath.Position++
+		tampered.AuditPath = &auditPath
+		assert.False(t, tampered.Verify(updateResult.MerkleRoot))
+
+		_, err = manager.DiscloseProperty(ctx, "docID", "address/zip/string")
+		assert.EqualError(t, err, "document docID=docID does not have key=address/zip/string")
+
+		// Manifests written before Merkle roots were introduced cannot be used.
+		store.append(manifestKey("legacyID"), []byte(`{"id":"legacyID","indexes":[0],"hash":"`+storeResult.Hash+`"}`))
+		_, err = manager.DiscloseProperty(ctx, "legacyID", "name/string")
+		assert.EqualError(t, err, "manifest of document docID=legacyID does not record a Merkle root")
+	}
+}
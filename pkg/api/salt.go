@@ -0,0 +1,36 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+)
+
+// saltValues returns a property list whose salted properties have their value
+// prefixed with a new random salt, as written in the Database.
+func saltValues(entryList doc.PropertyEntryList) (doc.PropertyEntryList, error) {
+	stored := make(doc.PropertyEntryList, len(entryList))
+	for i, entry := range entryList {
+		stored[i] = entry
+		if !entry.IsSalted() {
+			continue
+		}
+		value, err := doc.SaltValue(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to salt property '%s': %v", entry.KeyURI, err)
+		}
+		stored[i].Value = value
+	}
+	return stored, nil
+}
+
+// hasSaltedProperties checks if any property of a document is salted, in which
+// case its new versions keep their properties salted.
+func (d *documentDetails) hasSaltedProperties() bool {
+	for _, entry := range d.propertyEntryList {
+		if entry.IsSalted() {
+			return true
+		}
+	}
+	return false
+}
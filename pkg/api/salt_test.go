@@ -0,0 +1,182 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerSaltedProperties(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "age": 30, "active": true, "tags": ["kyc"]}`)
+
+	for _, writeMode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+		for _, stream := range []bool{false, true} {
+			if stream && writeMode == WriteModeAtomic {
+				// Streamed documents can not be written atomically.
+				continue
+			}
+			clientMock, store := newMemoryClientMock()
+			manager := Manager{
+				conf:   *DefaultConfig().WithNumberWorkers(2).WithWriteMode(writeMode).WithSaltedProperties(true),
+				client: clientMock,
+			}
+
+			ctx := context.Background()
+			var storeResult *StoreDocumentResult
+			var err error
+			if stream {
+				storeResult, err = manager.StoreDocumentStream(ctx, "docID", bytes.NewReader(jsonPayload))
+			} else {
+				storeResult, err = manager.StoreDocument(ctx, "docID", bytes.NewReader(jsonPayload))
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Every property is stored salted, under a marked key.
+			for _, entry := range store.entries[:len(store.entries)-1] {
+				assert.True(t, doc.IsSaltedKey(string(entry.Key)))
+				if string(entry.Key) == "docID/active/$bool" {
+					assert.Equal(t, doc.SaltSize+4, len(entry.Value.Payload))
+				}
+			}
+
+			getResult, err := manager.GetDocument(ctx, "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, string(jsonPayload), string(getResult.Payload))
+			assert.Equal(t, storeResult.Hash, getResult.Hash)
+			verified, err := manager.VerifyDocument(ctx, "docID", storeResult.Hash)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.True(t, verified)
+
+			// The same document has another hash, as its salts differ.
+			otherResult, err := manager.StoreDocument(ctx, "otherID", bytes.NewReader(jsonPayload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.NotEqual(t, storeResult.MerkleRoot, otherResult.MerkleRoot)
+
+			// The salt is revealed along with the proven property.
+			proof, err := manager.ProveProperty(ctx, "docID", "active/bool")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, "docID/active/$bool", proof.Key)
+			assert.Equal(t, []byte("true"), proof.Value)
+			assert.Equal(t, doc.SaltSize, len(proof.Salt))
+			assert.True(t, proof.Verify())
+
+			tampered := *proof
+			tampered.Salt = make([]byte, doc.SaltSize)
+			assert.False(t, tampered.Verify())
+
+			tampered = *proof
+			tampered.Salt = proof.Salt[:doc.SaltSize-1]
+			tampered.Value = append([]byte{proof.Salt[doc.SaltSize-1]}, proof.Value...)
+			assert.False(t, tampered.Verify())
+
+			tampered = *proof
+			tampered.Salt = nil
+			tampered.Value = append(append([]byte{}, proof.Salt...), proof.Value...)
+			assert.False(t, tampered.Verify())
+
+			disclosure, err := manager.DiscloseProperty(ctx, "docID", "active/$bool")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.True(t, disclosure.Verify(storeResult.MerkleRoot))
+
+			bundle, err := manager.ExportProofBundle(ctx, "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := bundle.Verify(BundleTrust{Hash: storeResult.Hash}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			payload, err := bundle.Payload()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, string(jsonPayload), string(payload))
+		}
+	}
+}
+
+func TestManagerSaltedProperties_Updates(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1).WithSaltedProperties(true),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	if _, err := manager.StoreDocument(ctx, "docID", strings.NewReader(`{"name": "John", "age": 30}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, err := manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Documents with salted properties keep them salted, whatever the mode of
+	// the manager, and unchanged properties keep their salt.
+	manager.conf.SaltedProperties = false
+	if _, err := manager.UpdateDocument(ctx, "docID", "/age", json.RawMessage(`31`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.MergeDocument(ctx, "docID", strings.NewReader(`{"city": "Metro City"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	after, err := manager.getDocumentDetails(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := map[string]uint64{}
+	for _, hash := range after.propertyHashList {
+		assert.True(t, doc.IsSaltedKey(hash.Key))
+		keys[hash.Key] = hash.Index
+	}
+	assert.Equal(t, 3, len(keys))
+	assert.Equal(t, before.findProperty("name/string").Index, keys["docID/name/$string"])
+	assert.NotEqual(t, before.findProperty("age/float64").Index, keys["docID/age/$float64"])
+
+	getResult, err := manager.GetDocument(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name": "John", "age": 31, "city": "Metro City"}`, string(getResult.Payload))
+
+	// Unsalted documents are salted when updated by a salting manager.
+	if _, err := manager.StoreDocument(ctx, "plainID", strings.NewReader(`{"name": "John", "age": 30}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manager.conf.SaltedProperties = true
+	tx := manager.Begin()
+	if err := tx.Update(ctx, "plainID", "/age", json.RawMessage(`31`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tx.Commit(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plain, err := manager.getDocumentDetails(ctx, "plainID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, plain.hasSaltedProperties())
+	getResult, err = manager.GetDocument(ctx, "plainID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"name": "John", "age": 31}`, string(getResult.Payload))
+}
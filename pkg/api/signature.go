@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+)
+
+// SignatureError is returned when the manifest of a document is not signed by
+// any of the trusted keys.
+type SignatureError struct {
+	DocID string
+	Index uint64
+	Err   error
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("manifest of document docID=%s at index %d is not trusted: %v", e.DocID, e.Index, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// signedPayload returns the canonical encoding of a manifest covered by its
+// signature, which is the manifest as stored, including its key ID, but without
+// the signature itself.
+func (om *ObjectManifest) signedPayload() ([]byte, error) {
+	unsigned := *om
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// sign signs the manifest with the given signer, recording the ID of its key.
+// Ed25519 signers sign the canonical encoding of the manifest, while ECDSA
+// P-256 signers sign its SHA-256 digest.
+func (om *ObjectManifest) sign(keyID string, signer crypto.Signer) error {
+	om.KeyID = keyID
+	payload, err := om.signedPayload()
+	if err != nil {
+		return err
+	}
+
+	switch key := signer.Public().(type) {
+	case ed25519.PublicKey:
+		om.Signature, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return fmt.Errorf("unsupported curve %s of signer", key.Params().Name)
+		}
+		digest := sha256.Sum256(payload)
+		om.Signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return fmt.Errorf("unsupported signer key type %T", key)
+	}
+
+	return err
+}
+
+// VerifySignature ensures that the manifest, as stored, is signed by one of the
+// given trusted keys, indexed by key ID. Keys are either ed25519.PublicKey or
+// *ecdsa.PublicKey on the P-256 curve.
+func (om *ObjectManifest) VerifySignature(trustedKeys map[string]crypto.PublicKey) error {
+	if len(om.Signature) == 0 {
+		return errors.New("manifest is not signed")
+	}
+	trustedKey, ok := trustedKeys[om.KeyID]
+	if !ok {
+		return fmt.Errorf("manifest is signed by untrusted key ID '%s'", om.KeyID)
+	}
+
+	payload, err := om.signedPayload()
+	if err != nil {
+		return err
+	}
+
+	var verified bool
+	switch key := trustedKey.(type) {
+	case ed25519.PublicKey:
+		verified = ed25519.Verify(key, payload, om.Signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		verified = key.Curve == elliptic.P256() && ecdsa.VerifyASN1(key, digest[:], om.Signature)
+	default:
+		return fmt.Errorf("unsupported key type %T of key ID '%s'", key, om.KeyID)
+	}
+	if !verified {
+		return fmt.Errorf("signature of key ID '%s' failed verification", om.KeyID)
+	}
+
+	return nil
+}
+
+// encodeManifest encodes a manifest to be written in the Database, signing it
+// first if a signer is configured.
+func (m *Manager) encodeManifest(om *ObjectManifest) ([]byte, error) {
+	if m.conf.Signer != nil {
+		if err := om.sign(m.conf.SignerKeyID, m.conf.Signer); err != nil {
+			return nil, fmt.Errorf("unable to sign object manifest: %v", err)
+		}
+	}
+
+	value, err := json.Marshal(om)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshall object maifest: %v", err)
+	}
+	return value, nil
+}
+
+// checkManifestSignature ensures that a manifest read from the Database, before
+// its indexes are resolved, is signed by a trusted key. Signatures are only
+// checked if trusted keys are configured.
+func (m *Manager) checkManifestSignature(om *ObjectManifest, manifestIndex uint64) error {
+	if len(m.conf.TrustedKeys) == 0 {
+		return nil
+	}
+	if err := om.VerifySignature(m.conf.TrustedKeys); err != nil {
+		return &SignatureError{DocID: om.ObjectID, Index: manifestIndex, Err: err}
+	}
+	return nil
+}
+
+// checkPreviousManifest ensures that the latest manifest of a document records
+// as its previous index the one of the manifest written right before it, if
+// any, so that an earlier manifest signed by a trusted key cannot be replayed
+// as the latest version of the document. As the signature covers the previous
+// index, it is only checked if trusted keys are configured.
+func (m *Manager) checkPreviousManifest(ctx context.Context, om *ObjectManifest, manifestIndex uint64) error {
+	if len(m.conf.TrustedKeys) == 0 {
+		return nil
+	}
+	items, err := m.client.History(ctx, &immuschema.HistoryOptions{Key: manifestKey(om.ObjectID)})
+	if err != nil {
+		return err
+	}
+
+	var previousIndex uint64
+	for _, item := range items.GetItems() {
+		if item.Index < manifestIndex && item.Index > previousIndex {
+			previousIndex = item.Index
+		}
+	}
+	return checkPreviousIndex(om, manifestIndex, previousIndex)
+}
+
+// checkPreviousIndex ensures that a manifest records the given previous index.
+func checkPreviousIndex(om *ObjectManifest, manifestIndex, previousIndex uint64) error {
+	if om.PreviousIndex != previousIndex {
+		return &SignatureError{
+			DocID: om.ObjectID,
+			Index: manifestIndex,
+			Err:   fmt.Errorf("manifest records previous index %d, while the previous manifest is at index %d", om.PreviousIndex, previousIndex),
+		}
+	}
+	return nil
+}
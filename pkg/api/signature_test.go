@@ -0,0 +1,288 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerSignedManifests(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	for _, signer := range []crypto.Signer{edKey, ecKey} {
+		for _, writeMode := range []WriteMode{WriteModeConcurrent, WriteModeAtomic} {
+			clientMock, _ := newMemoryClientMock()
+			manager := Manager{
+				conf: *DefaultConfig().WithNumberWorkers(2).WithWriteMode(writeMode).
+					WithSigner("key1", signer).WithTrustedKey("key1", signer.Public()),
+				client: clientMock,
+			}
+
+			ctx := context.Background()
+			storeResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader(jsonPayload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			getResult, err := manager.GetDocument(ctx, "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, string(jsonPayload), string(getResult.Payload))
+
+			verified, err := manager.VerifyDocument(ctx, "docID", storeResult.Hash)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.True(t, verified)
+
+			docDetails, err := manager.getDocumentDetails(ctx, "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, "key1", docDetails.objectManifest.KeyID)
+			assert.NotEmpty(t, docDetails.objectManifest.Signature)
+
+			// Updates, transactions and deletions sign their manifests as well.
+			if _, err := manager.UpdateDocument(ctx, "docID", "/age", json.RawMessage(`31`)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tx := manager.Begin()
+			if err := tx.Store("otherID", bytes.NewReader(jsonPayload)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := tx.Commit(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := manager.GetDocument(ctx, "otherID"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := manager.DeleteDocument(ctx, "docID"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			history, err := manager.GetDocumentHistory(ctx, "docID")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Len(t, history, 3)
+		}
+	}
+}
+
+func TestManagerSignedManifests_Rejected(t *testing.T) {
+	_, signerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jsonPayload := []byte(`{"name": "John", "age": 30}`)
+
+	tests := []struct {
+		name   string
+		writer *Config
+		reader *Config
+	}{
+		{
+			name:   "unsigned manifest",
+			writer: DefaultConfig(),
+			reader: DefaultConfig().WithTrustedKey("key1", signerKey.Public()),
+		},
+		{
+			name:   "untrusted key ID",
+			writer: DefaultConfig().WithSigner("key2", signerKey),
+			reader: DefaultConfig().WithTrustedKey("key1", signerKey.Public()),
+		},
+		{
+			name:   "signature of another key",
+			writer: DefaultConfig().WithSigner("key1", signerKey),
+			reader: DefaultConfig().WithTrustedKey("key1", otherPublicKey),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientMock, _ := newMemoryClientMock()
+			writer := Manager{conf: *test.writer.WithNumberWorkers(2), client: clientMock}
+			reader := Manager{conf: *test.reader, client: clientMock}
+
+			ctx := context.Background()
+			storeResult, err := writer.StoreDocument(ctx, "docID", bytes.NewReader(jsonPayload))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var signatureErr *SignatureError
+			_, err = reader.GetDocument(ctx, "docID")
+			assert.True(t, errors.As(err, &signatureErr))
+			assert.Equal(t, "docID", signatureErr.DocID)
+			assert.Equal(t, storeResult.Index, signatureErr.Index)
+
+			verified, err := reader.VerifyDocument(ctx, "docID", storeResult.Hash)
+			assert.True(t, errors.As(err, &signatureErr))
+			assert.False(t, verified)
+		})
+	}
+}
+
+func TestManagerSignedManifests_Forged(t *testing.T) {
+	_, signerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf: *DefaultConfig().WithNumberWorkers(2).
+			WithSigner("key1", signerKey).WithTrustedKey("key1", signerKey.Public()),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	storeResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"name": "John", "age": 30}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A manifest dropping a property, written with the signature of the
+	// genuine one, is rejected.
+	manifest := &ObjectManifest{}
+	if err := json.Unmarshal(store.entries[storeResult.Index].Value.Payload, manifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest.Indexes = manifest.Indexes[:1]
+	forgedValue, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store.append(manifestKey("docID"), forgedValue)
+
+	var signatureErr *SignatureError
+	_, err = manager.GetDocument(ctx, "docID")
+	assert.True(t, errors.As(err, &signatureErr))
+	assert.EqualError(t, errors.Unwrap(err), "signature of key ID 'key1' failed verification")
+}
+
+func TestManagerSignedManifests_Replayed(t *testing.T) {
+	_, signerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf: *DefaultConfig().WithWriteMode(WriteModeAtomic).
+			WithSigner("key1", signerKey).WithTrustedKey("key1", signerKey.Public()),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	storeResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"amount": "100"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := manager.PutAttachment(ctx, "docID", "scan.pdf", bytes.NewReader([]byte("scan"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	signedValue := store.entries[len(store.entries)-1].Value.Payload
+
+	// The signed manifest of a batch commits the content of its properties,
+	// hence replaying it after a forged property is rejected.
+	store.append([]byte("docID/amount/string"), []byte("999999"))
+	store.append(manifestKey("docID"), store.entries[storeResult.Index].Value.Payload)
+	_, err = manager.GetDocument(ctx, "docID")
+	assert.EqualError(t, err, "properties of document ID 'docID' do not match its manifest at index 6")
+
+	// A signed manifest cannot be copied to another document either.
+	store.append(manifestKey("otherID"), signedValue)
+	_, err = manager.GetDocument(ctx, "otherID")
+	assert.EqualError(t, err, "manifest manifest/otherID at index 7 describes document ID 'docID'")
+	_, err = manager.VerifyDocument(ctx, "otherID", storeResult.Hash)
+	assert.Error(t, err)
+	_, err = manager.GetAttachment(ctx, "otherID", "scan.pdf", &bytes.Buffer{})
+	assert.EqualError(t, err, "manifest manifest/otherID at index 7 describes document ID 'docID'")
+}
+
+func TestManagerSignedManifests_Stale(t *testing.T) {
+	_, signerKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf: *DefaultConfig().WithNumberWorkers(2).
+			WithSigner("key1", signerKey).WithTrustedKey("key1", signerKey.Public()),
+		client: clientMock,
+	}
+
+	ctx := context.Background()
+	firstResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"amount": "100"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secondResult, err := manager.StoreDocument(ctx, "docID", bytes.NewReader([]byte(`{"amount": "200"}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	versions, err := manager.GetDocumentHistory(ctx, "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, versions, 2)
+	assert.Equal(t, secondResult.Index, versions[1].Index)
+	assert.Equal(t, firstResult.Index, versions[1].details.objectManifest.PreviousIndex)
+
+	// The genuine manifest of the first version, whose properties are
+	// untouched, cannot be replayed as the latest version.
+	staleIndex := store.append(manifestKey("docID"), store.entries[firstResult.Index].Value.Payload)
+
+	var signatureErr *SignatureError
+	_, err = manager.GetDocument(ctx, "docID")
+	assert.True(t, errors.As(err, &signatureErr))
+	assert.Equal(t, staleIndex, signatureErr.Index)
+	assert.EqualError(t, errors.Unwrap(err), fmt.Sprintf(
+		"manifest records previous index 0, while the previous manifest is at index %d", secondResult.Index))
+	_, err = manager.VerifyDocument(ctx, "docID", firstResult.Hash)
+	assert.True(t, errors.As(err, &signatureErr))
+	_, err = manager.GetDocumentHistory(ctx, "docID")
+	assert.True(t, errors.As(err, &signatureErr))
+}
+
+func TestManagerSignedManifests_UnsupportedSigner(t *testing.T) {
+	signerKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(2).WithSigner("key1", signerKey),
+		client: clientMock,
+	}
+
+	_, err = manager.StoreDocument(context.Background(), "docID", bytes.NewReader([]byte(`{"name": "John"}`)))
+	assert.Error(t, err)
+}
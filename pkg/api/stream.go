@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+	"github.com/oscarpfernandez/immudbcc/pkg/worker"
+)
+
+// Streamed properties are handed to the write workers in windows: a window is
+// only handed over once every property of the previous one is written. Their
+// indexes are written out in index blocks as the properties are written. Both
+// are variables so that tests can use small documents.
+var (
+	streamWindowSize = 256
+	indexBlockSize   = 4096
+)
+
+// streamResult represents the outcome of flattening a streamed document.
+type streamResult struct {
+	count int // Number of property entries handed to the workers.
+	err   error
+}
+
+// StoreDocumentStream saves a JSON document in the database as done by
+// StoreDocument, although the payload is flattened as it is read, and its
+// properties are handed straight to the write workers, their values not being
+// kept. Memory is bounded: properties are written in windows, each one being
+// folded into the document's hash and Merkle root once written, while their
+// indexes are written out in index blocks, chained from the manifest. Streamed
+// documents can not be written atomically, as their properties would be
+// gathered in a single batch.
+func (m *Manager) StoreDocumentStream(ctx context.Context, docID string, r io.Reader) (*StoreDocumentResult, error) {
+	if m.conf.WriteMode == WriteModeAtomic {
+		return nil, fmt.Errorf("failed to store document ID '%s': streamed documents can not be written atomically", docID)
+	}
+
+	workers := worker.NewWriteWorkerPool(m.conf.NumberWorkers, m.client)
+	if err := workers.StartWorkers(ctx); err != nil {
+		return nil, err
+	}
+	defer workers.Stop()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	entries := make(chan doc.PropertyEntry)
+	resultChan, _, errChan := workers.WriteStream(entries)
+
+	acks := make(chan struct{}, 1)
+	doneChan := make(chan streamResult, 1)
+	go func() {
+		defer close(entries)
+		count := 0
+		err := doc.StreamPropertyList(docID, r, func(entry doc.PropertyEntry) error {
+			if count > 0 && count%streamWindowSize == 0 {
+				// Wait for the previous window to be written.
+				select {
+				case <-acks:
+				case <-streamCtx.Done():
+					return streamCtx.Err()
+				}
+			}
+			if m.conf.SaltedProperties {
+				salted, err := saltValues(doc.PropertyEntryList{{KeyURI: doc.SaltedKey(entry.KeyURI), Value: entry.Value}})
+				if err != nil {
+					return err
+				}
+				entry = salted[0]
+			}
+			select {
+			case entries <- entry:
+				count++
+				return nil
+			case <-streamCtx.Done():
+				return streamCtx.Err()
+			}
+		}, m.docOptions()...)
+		doneChan <- streamResult{count: count, err: err}
+	}()
+
+	builder := newStreamManifest(docID)
+	var window doc.PropertyHashList
+	var errList []string
+	// Every entry handed to the workers results in either a hash or an error,
+	// while their number is only known once the payload is fully read.
+	counter, inWindow, total := 0, 0, -1
+	for total < 0 || counter < total {
+		select {
+		case hash := <-resultChan:
+			if hash == nil {
+				continue
+			}
+			window = append(window, hash)
+			counter++
+			inWindow++
+		case err := <-errChan:
+			if err == nil {
+				continue
+			}
+			errList = append(errList, err.Error())
+			counter++
+			inWindow++
+		case result := <-doneChan:
+			total = result.count
+			if result.err != nil {
+				errList = append(errList, result.err.Error())
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to store document ID '%s': %v", docID, ctx.Err())
+		}
+
+		if inWindow < streamWindowSize && (total < 0 || counter < total) {
+			continue
+		}
+		// The window is written: no other entry is being written until the
+		// next one is handed over, which is not done on failure.
+		if len(errList) > 0 {
+			break
+		}
+		builder.add(window)
+		if len(builder.indexes) >= indexBlockSize {
+			if err := m.writeIndexBlock(ctx, builder); err != nil {
+				return nil, fmt.Errorf("failed to store document ID '%s': %v", docID, err)
+			}
+		}
+		window, inWindow = window[:0], 0
+		if total < 0 {
+			acks <- struct{}{}
+		}
+	}
+
+	if len(errList) > 0 {
+		return nil, fmt.Errorf("failed to store document ID '%s': %v", docID, strings.Join(errList, "; "))
+	}
+
+	return m.storeDocumentManifest(ctx, builder.manifest())
+}
+
+// streamManifest builds the manifest of a streamed document from its written
+// properties, window by window, keeping only the indexes not yet written out in
+// an index block.
+type streamManifest struct {
+	docID      string
+	hash       hash.Hash
+	merkle     doc.MerkleBuilder
+	indexes    []uint64
+	indexBlock uint64
+}
+
+// newStreamManifest returns the builder of the manifest of a streamed document.
+func newStreamManifest(docID string) *streamManifest {
+	return &streamManifest{docID: docID, hash: sha256.New()}
+}
+
+// add folds a window of written properties into the manifest. Properties of a
+// window are written after those of the previous ones, hence, once sorted, the
+// properties are added in order of index, as done by PropertyHashList.
+func (s *streamManifest) add(window doc.PropertyHashList) {
+	sort.Sort(window)
+	for _, hash := range window {
+		_, _ = s.hash.Write(hash.Hash)
+		s.merkle.Append(hash.Content)
+		s.indexes = append(s.indexes, hash.Index)
+	}
+}
+
+// manifest returns the manifest of the streamed document.
+func (s *streamManifest) manifest() *ObjectManifest {
+	return &ObjectManifest{
+		ObjectID:   s.docID,
+		Indexes:    s.indexes,
+		IndexBlock: s.indexBlock,
+		Hash:       hex.EncodeToString(s.hash.Sum(nil)),
+		MerkleRoot: s.merkle.Root(),
+	}
+}
+
+// writeIndexBlock writes out the pending indexes of a streamed document in an
+// index block, chained to the previous one.
+func (m *Manager) writeIndexBlock(ctx context.Context, s *streamManifest) error {
+	value, err := json.Marshal(&indexBlock{Indexes: s.indexes, Previous: s.indexBlock})
+	if err != nil {
+		return err
+	}
+	index, err := m.client.SafeSet(ctx, indexBlockKey(s.docID), value)
+	if err != nil {
+		return err
+	}
+	s.indexes, s.indexBlock = nil, index.Index
+	return nil
+}
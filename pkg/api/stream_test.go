@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerStoreDocumentStream(t *testing.T) {
+	payload, err := ioutil.ReadFile("../../testdata/example1.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(5),
+		client: clientMock,
+	}
+
+	if _, err := manager.StoreDocument(context.Background(), "docID", bytes.NewReader(payload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	storeResult, err := manager.StoreDocumentStream(context.Background(), "streamDocID", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	getResult, err := manager.GetDocument(context.Background(), "streamDocID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, string(wantResult.Payload), string(getResult.Payload))
+	assert.Equal(t, storeResult.Index, getResult.Index)
+	assert.Equal(t, storeResult.Hash, getResult.Hash)
+	assert.Equal(t, storeResult.MerkleRoot, getResult.MerkleRoot)
+
+	verified, err := manager.VerifyDocument(context.Background(), "streamDocID", storeResult.Hash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, verified)
+
+	_, err = manager.StoreDocumentStream(context.Background(), "badDocID", strings.NewReader(`{"a": 1, "a": 2}`))
+	assert.EqualError(t, err, "failed to store document ID 'badDocID': unable to unmarshall payload: duplicate key 'a'")
+
+	// Streamed documents are never gathered in a single batch.
+	manager.conf.WriteMode = WriteModeAtomic
+	_, err = manager.StoreDocumentStream(context.Background(), "atomicDocID", bytes.NewReader(payload))
+	assert.EqualError(t, err, "failed to store document ID 'atomicDocID': streamed documents can not be written atomically")
+}
+
+func TestManagerStoreDocumentStream_IndexBlocks(t *testing.T) {
+	defer func(windowSize, blockSize int) {
+		streamWindowSize, indexBlockSize = windowSize, blockSize
+	}(streamWindowSize, indexBlockSize)
+	streamWindowSize, indexBlockSize = 4, 10
+
+	var elements []string
+	for i := 0; i < 44; i++ {
+		elements = append(elements, strconv.Itoa(i))
+	}
+	payload := []byte(`{"elements": [` + strings.Join(elements, ", ") + `]}`)
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(3),
+		client: clientMock,
+	}
+
+	storeResult, err := manager.StoreDocumentStream(context.Background(), "docID", bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 44 elements and the array, written in 12 windows: the indexes of every 3
+	// windows are written out in a block, the last 9 ones in the manifest.
+	var blocks []uint64
+	for index, entry := range store.entries {
+		if bytes.Equal(entry.Key, indexBlockKey("docID")) {
+			blocks = append(blocks, uint64(index))
+		}
+	}
+	assert.Len(t, blocks, 3)
+
+	storedManifest := &ObjectManifest{}
+	if err := json.Unmarshal(store.entries[storeResult.Index].Value.Payload, storedManifest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, blocks[2], storedManifest.IndexBlock)
+	assert.Len(t, storedManifest.Indexes, 9)
+
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(payload), string(getResult.Payload))
+	assert.Equal(t, storeResult.Hash, getResult.Hash)
+	assert.Equal(t, storeResult.MerkleRoot, getResult.MerkleRoot)
+
+	docDetails, err := manager.getDocumentDetails(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, docDetails.objectManifest.Indexes, 45)
+	assert.Equal(t, docDetails.propertyHashList.Hash(), storeResult.Hash)
+	assert.Equal(t, docDetails.propertyHashList.MerkleRoot(), storeResult.MerkleRoot)
+	assert.Equal(t, []uint64{blocks[2], blocks[1], blocks[0]}, docDetails.indexBlocks)
+
+	bundle, err := manager.ExportProofBundle(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, bundle.IndexBlocks, 3)
+	manifest, err := bundle.Verify(BundleTrust{Hash: storeResult.Hash})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, manifest.Indexes, 45)
+
+	// Every index block is required.
+	bundle.IndexBlocks = bundle.IndexBlocks[1:]
+	_, err = bundle.Verify(BundleTrust{Hash: storeResult.Hash})
+	assert.EqualError(t, err, fmt.Sprintf("proof bundle is missing the index block at index %d", blocks[2]))
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+)
+
+// ErrTransactionClosed is returned when using a transaction after its commit.
+var ErrTransactionClosed = errors.New("transaction is closed")
+
+// Transaction gathers the writes of several documents, committing all their
+// properties and manifests in a single batch operation. This guarantees that
+// either every document of the transaction is written, or none is.
+// A Transaction is not safe for concurrent use.
+type Transaction struct {
+	manager *Manager
+	docs    []*transactionDocument
+	closed  bool
+}
+
+// transactionDocument represents a document gathered in a transaction.
+type transactionDocument struct {
+	docID     string
+	base      *documentDetails      // Version on which the document is based, if loaded from the Database.
+	entryList doc.PropertyEntryList // Properties of the new version of the document.
+}
+
+// Begin starts a new transaction.
+func (m *Manager) Begin() *Transaction {
+	return &Transaction{manager: m}
+}
+
+// Store adds to the transaction the write of a JSON document, replacing any
+// previous version of said document.
+func (t *Transaction) Store(docID string, r io.Reader) error {
+	if t.closed {
+		return ErrTransactionClosed
+	}
+
+	entryList, err := doc.RawToPropertyList(docID, r, t.manager.docOptions()...)
+	if err != nil {
+		return err
+	}
+
+	d := t.document(docID)
+	d.base = nil
+	d.entryList = entryList
+
+	return nil
+}
+
+// Update adds to the transaction the update of a given property of a document,
+// provided its path as a JSON Pointer and its new JSON value, as described by
+// Manager.UpdateDocument. The document is either the one stored previously
+// within this transaction, or otherwise the current version in the Database.
+// In the latter case, the transaction fails to commit if the document is
+// modified in the meantime.
+func (t *Transaction) Update(ctx context.Context, docID string, path string, value json.RawMessage) error {
+	if t.closed {
+		return ErrTransactionClosed
+	}
+
+	rawValue, err := doc.DecodeValue(value, t.manager.docOptions()...)
+	if err != nil {
+		return fmt.Errorf("invalid value of key=%s: %v", path, err)
+	}
+
+	d := t.findDocument(docID)
+	if d == nil {
+		docDetails, err := t.manager.getDocumentDetails(ctx, docID)
+		if err != nil {
+			return err
+		}
+		d = t.document(docID)
+		d.base = docDetails
+		d.entryList = append(doc.PropertyEntryList{}, docDetails.propertyEntryList...)
+	}
+
+	rawObject, err := doc.SetValue(doc.PropertyListToRaw(d.entryList), path, rawValue)
+	if err != nil {
+		return fmt.Errorf("unable to update document docID=%s: %v", docID, err)
+	}
+	d.entryList = doc.ObjectToPropertyList(docID, rawObject)
+
+	return nil
+}
+
+// Commit writes every document of the transaction in a single batch operation,
+// returning a result covering all of them.
+func (t *Transaction) Commit(ctx context.Context) (*CommitResult, error) {
+	if t.closed {
+		return nil, ErrTransactionClosed
+	}
+	if len(t.docs) == 0 {
+		return nil, errors.New("transaction has no documents")
+	}
+	t.closed = true
+
+	batchDocs := make([]*batchDocument, len(t.docs))
+	for i, d := range t.docs {
+		batchDocs[i] = newBatchDocument(d.docID, d.base, d.entryList, t.manager.conf.SaltedProperties)
+		if d.base != nil {
+			batchDocs[i].expected = ExpectedVersion{Index: &d.base.objectManifestIndex}
+		}
+	}
+
+	result, err := t.manager.writeBatch(ctx, batchDocs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Printf("Transaction commit succesfull: index(%d) - documents(%d)", result.Index, len(result.Documents))
+
+	return result, nil
+}
+
+// findDocument returns the document with the given ID gathered in the
+// transaction, or nil if there is none.
+func (t *Transaction) findDocument(docID string) *transactionDocument {
+	for _, d := range t.docs {
+		if d.docID == docID {
+			return d
+		}
+	}
+	return nil
+}
+
+// document returns the document with the given ID gathered in the transaction,
+// adding it if there is none.
+func (t *Transaction) document(docID string) *transactionDocument {
+	if d := t.findDocument(docID); d != nil {
+		return d
+	}
+	d := &transactionDocument{docID: docID}
+	t.docs = append(t.docs, d)
+	return d
+}
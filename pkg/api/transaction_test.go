@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	immuschema "github.com/codenotary/immudb/pkg/api/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	orderPayload := []byte(`{"id": "order-1", "items": ["widget"], "status": "pending"}`)
+	inventoryPayload := []byte(`{"widget": {"stock": 10}, "gadget": {"stock": 5}}`)
+
+	clientMock, store := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig(),
+		client: clientMock,
+	}
+
+	// Store the inventory with the default write mode.
+	if _, err := manager.StoreDocument(context.Background(), "inventory", bytes.NewReader(inventoryPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	numEntries := len(store.entries)
+
+	tx := manager.Begin()
+	if err := tx.Store("order", bytes.NewReader(orderPayload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Update(context.Background(), "order", "/status", json.RawMessage(`"confirmed"`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Update(context.Background(), "inventory", "/widget/stock", json.RawMessage(`9`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := tx.Update(context.Background(), "inventory", "/widget/stock/units", json.RawMessage(`"kg"`))
+	assert.EqualError(t, err, "unable to update document docID=inventory: key 'units' can not be set on a scalar value")
+
+	result, err := tx.Commit(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Four order properties, one inventory property and two manifests.
+	assert.Len(t, store.entries, numEntries+7)
+	assert.Equal(t, uint64(len(store.entries)-1), result.Index)
+	assert.Len(t, result.Documents, 2)
+	assert.Equal(t, result.documentsHash(), result.Hash)
+
+	expPayloads := map[string][]byte{
+		"order":     []byte(`{"id": "order-1", "items": ["widget"], "status": "confirmed"}`),
+		"inventory": []byte(`{"widget": {"stock": 9}, "gadget": {"stock": 5}}`),
+	}
+	for docID, expPayload := range expPayloads {
+		getResult, err := manager.GetDocument(context.Background(), docID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.JSONEq(t, string(expPayload), string(getResult.Payload))
+		assert.Equal(t, result.Documents[docID].Index, getResult.Index)
+		assert.Equal(t, result.Documents[docID].Hash, getResult.Hash)
+
+		isValid, err := manager.VerifyDocument(context.Background(), docID, result.Documents[docID].Hash)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.True(t, isValid)
+	}
+
+	// The transaction can not be reused.
+	_, err = tx.Commit(context.Background())
+	assert.Equal(t, ErrTransactionClosed, err)
+	assert.Equal(t, ErrTransactionClosed, tx.Store("order", bytes.NewReader(orderPayload)))
+}
+
+func TestTransactionCommit_Failure(t *testing.T) {
+	clientMock, store := newMemoryClientMock()
+	clientMock.execAllOpsFn = func(ctx context.Context, ops *immuschema.Ops) (*immuschema.Index, error) {
+		return nil, errors.New("batch error")
+	}
+	manager := Manager{
+		conf:   *DefaultConfig(),
+		client: clientMock,
+	}
+
+	tx := manager.Begin()
+	if err := tx.Store("order", bytes.NewReader([]byte(`{"id": "order-1"}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tx.Store("inventory", bytes.NewReader([]byte(`{"widget": 10}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := tx.Commit(context.Background())
+	assert.EqualError(t, err, "failed to commit transaction: batch error")
+	assert.Empty(t, store.entries)
+
+	_, err = manager.Begin().Commit(context.Background())
+	assert.EqualError(t, err, "transaction has no documents")
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/oscarpfernandez/immudbcc/pkg/doc"
+)
+
+// StoreValue saves a Go value as a document in the database, mapping its
+// fields straight to properties, as described by doc.ValueToPropertyList.
+// Integers are stored as their exact decimal text, hence without float loss.
+func (m *Manager) StoreValue(ctx context.Context, docID string, v interface{}) (*StoreDocumentResult, error) {
+	entryList, err := doc.ValueToPropertyList(docID, v, m.docOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert value of document ID '%s': %v", docID, err)
+	}
+
+	return m.storeDocument(ctx, docID, entryList)
+}
+
+// GetInto reads a document, verified as done by GetDocument, into the Go value
+// pointed to by v.
+func (m *Manager) GetInto(ctx context.Context, docID string, v interface{}) error {
+	docDetails, err := m.getDocumentDetails(ctx, docID)
+	if err != nil {
+		return err
+	}
+	if docDetails.objectManifest.Deleted {
+		return &NotFoundError{DocID: docID}
+	}
+
+	if err := doc.PropertyListToValue(docDetails.propertyEntryList, v); err != nil {
+		return fmt.Errorf("unable to convert document ID '%s': %v", docID, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testInvoice struct {
+	ID       int64     `json:"id"`
+	Customer string    `json:"customer"`
+	IssuedAt time.Time `json:"issuedAt"`
+	Document []byte    `json:"document"`
+	Lines    []struct {
+		Item   string `json:"item"`
+		Amount int64  `json:"amount"`
+	} `json:"lines"`
+}
+
+func TestManagerStoreValueGetInto(t *testing.T) {
+	clientMock, _ := newMemoryClientMock()
+	manager := Manager{
+		conf:   *DefaultConfig().WithNumberWorkers(1),
+		client: clientMock,
+	}
+
+	invoice := testInvoice{
+		ID:       9007199254740993,
+		Customer: "John",
+		IssuedAt: time.Date(2020, 8, 1, 10, 30, 0, 0, time.UTC),
+		Document: []byte("%PDF-1.4"),
+	}
+	invoice.Lines = append(invoice.Lines, struct {
+		Item   string `json:"item"`
+		Amount int64  `json:"amount"`
+	}{Item: "widget", Amount: -9007199254740993})
+
+	storeResult, err := manager.StoreValue(context.Background(), "docID", invoice)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got testInvoice
+	if err := manager.GetInto(context.Background(), "docID", &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, invoice, got)
+
+	// Typed documents are regular documents.
+	getResult, err := manager.GetDocument(context.Background(), "docID")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, storeResult.Hash, getResult.Hash)
+	assert.Contains(t, string(getResult.Payload), `"id": 9007199254740993`)
+
+	var gotCustomer struct {
+		Customer int `json:"customer"`
+	}
+	err = manager.GetInto(context.Background(), "docID", &gotCustomer)
+	assert.EqualError(t, err, "unable to convert document ID 'docID': invalid value at '/customer': cannot decode string into int")
+
+	if _, err := manager.DeleteDocument(context.Background(), "docID"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = manager.GetInto(context.Background(), "docID", &got)
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = manager.StoreValue(context.Background(), "docID", make(chan int))
+	assert.EqualError(t, err, "unable to convert value of document ID 'docID': unsupported type chan int")
+}
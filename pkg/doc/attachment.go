@@ -0,0 +1,186 @@
+package doc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultChunkSize is the default size of the chunks of attachments.
+const DefaultChunkSize = 64 * 1024
+
+// Attachment property types.
+const (
+	attachmentType = "attachment"
+	chunkType      = "chunk"
+)
+
+// AttachmentInfo describes the binary content attached to a document, as
+// stored in its attachment property. The content itself is split into chunk
+// properties of a fixed size. Attachments written in a batch cannot know in
+// advance the indexes of their chunks, hence they record instead the offsets
+// between the index of the attachment property and those of the chunks.
+type AttachmentInfo struct {
+	Size      int64    `json:"size"`              // Size of the content, in bytes.
+	ChunkSize int      `json:"chunkSize"`         // Size of every chunk but the last one.
+	Digest    string   `json:"sha256"`            // Hex encoded SHA-256 digest of the content.
+	Indexes   []uint64 `json:"indexes,omitempty"` // DB indexes of the chunks, in order.
+	Offsets   []uint64 `json:"offsets,omitempty"` // Offsets of the chunks, in order, if written in a batch.
+}
+
+// Chunks returns the number of chunks of the attachment.
+func (a *AttachmentInfo) Chunks() int {
+	if a.ChunkSize <= 0 {
+		return 0
+	}
+	return int((a.Size + int64(a.ChunkSize) - 1) / int64(a.ChunkSize))
+}
+
+// AttachmentKey returns the key of the property describing a named attachment
+// of a document.
+func AttachmentKey(docID, name string) string {
+	return attachmentKeyURI(docID, name, nil, attachmentType)
+}
+
+// ChunkKey returns the key of the property holding a given chunk of a named
+// attachment of a document.
+func ChunkKey(docID, name string, chunk int) string {
+	return attachmentKeyURI(docID, name, []string{strconv.Itoa(chunk)}, chunkType)
+}
+
+// attachmentKeyURI returns the key of an attachment property, escaping its name
+// if required.
+func attachmentKeyURI(docID, name string, segments []string, vType string) string {
+	escapedName, escaped := EscapeKey(name)
+	keyURI := strings.Join(append([]string{docID, escapedName}, segments...), "/") + "/" + vType
+	if escaped {
+		keyURI = markEscapedKey(keyURI)
+	}
+	return keyURI
+}
+
+// PropertyAttachment converts the description of a named attachment to a
+// PropertyEntry.
+func PropertyAttachment(docID, name string, info *AttachmentInfo) PropertyEntry {
+	value, _ := json.Marshal(info)
+	return PropertyEntry{
+		KeyURI: AttachmentKey(docID, name),
+		Value:  value,
+	}
+}
+
+// PropertyChunk converts a chunk of a named attachment to a PropertyEntry.
+func PropertyChunk(docID, name string, chunk int, data []byte) PropertyEntry {
+	return PropertyEntry{
+		KeyURI: ChunkKey(docID, name, chunk),
+		Value:  data,
+	}
+}
+
+// IsAttachment checks if a property belongs to an attachment, rather than to
+// the document's raw object.
+func (p PropertyEntry) IsAttachment() bool {
+	vType := keyType(p.KeyURI)
+	return vType == attachmentType || vType == chunkType
+}
+
+// ParseAttachment returns the description of an attachment given the value and
+// the index of its property, the indexes of the chunks of attachments written
+// in a batch being resolved from their offsets.
+func ParseAttachment(value []byte, index uint64) (*AttachmentInfo, error) {
+	info := &AttachmentInfo{}
+	if err := json.Unmarshal(value, info); err != nil {
+		return nil, fmt.Errorf("invalid attachment: %v", err)
+	}
+	if len(info.Offsets) > 0 {
+		if len(info.Indexes) > 0 {
+			return nil, fmt.Errorf("invalid attachment: both indexes and offsets")
+		}
+		for _, offset := range info.Offsets {
+			if offset == 0 || offset > index {
+				return nil, fmt.Errorf("invalid attachment: invalid chunk offset %d", offset)
+			}
+			info.Indexes = append(info.Indexes, index-offset)
+		}
+		info.Offsets = nil
+	}
+	if len(info.Indexes) != info.Chunks() {
+		return nil, fmt.Errorf("invalid attachment: %d chunks expected, got %d", info.Chunks(), len(info.Indexes))
+	}
+	return info, nil
+}
+
+// SplitAttachment reads binary content, calling fn with each chunk of the
+// given size as soon as it is read, so that the content is never held in
+// memory as a whole. It returns the description of the attachment, without the
+// indexes of its chunks. The data passed to fn must not be retained.
+func SplitAttachment(r io.Reader, chunkSize int, fn func(chunk int, data []byte) error) (*AttachmentInfo, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d", chunkSize)
+	}
+
+	info := &AttachmentInfo{ChunkSize: chunkSize}
+	digest := sha256.New()
+	buf := make([]byte, chunkSize)
+	for chunk := 0; ; chunk++ {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			_, _ = digest.Write(buf[:n])
+			info.Size += int64(n)
+			if err := fn(chunk, buf[:n]); err != nil {
+				return nil, err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	info.Digest = hex.EncodeToString(digest.Sum(nil))
+
+	return info, nil
+}
+
+// AttachmentVerifier checks that the chunks of an attachment, read in order,
+// match its description.
+type AttachmentVerifier struct {
+	info   *AttachmentInfo
+	digest hash.Hash
+	chunk  int
+	size   int64
+}
+
+// NewAttachmentVerifier returns a verifier of the chunks of an attachment.
+func NewAttachmentVerifier(info *AttachmentInfo) *AttachmentVerifier {
+	return &AttachmentVerifier{info: info, digest: sha256.New()}
+}
+
+// Write checks the next chunk of the attachment.
+func (v *AttachmentVerifier) Write(data []byte) (int, error) {
+	last := v.chunk == v.info.Chunks()-1
+	if v.chunk >= v.info.Chunks() || (!last && len(data) != v.info.ChunkSize) || len(data) == 0 || len(data) > v.info.ChunkSize {
+		return 0, fmt.Errorf("chunk #%d of attachment has invalid size %d", v.chunk, len(data))
+	}
+	v.chunk++
+	v.size += int64(len(data))
+	return v.digest.Write(data)
+}
+
+// Verify checks that every chunk was read, and that the content's digest
+// matches the attachment's.
+func (v *AttachmentVerifier) Verify() error {
+	if v.chunk != v.info.Chunks() || v.size != v.info.Size {
+		return fmt.Errorf("attachment is incomplete: read %d bytes of %d", v.size, v.info.Size)
+	}
+	if digest := hex.EncodeToString(v.digest.Sum(nil)); digest != v.info.Digest {
+		return fmt.Errorf("attachment digest mismatch: expected %s, got %s", v.info.Digest, digest)
+	}
+	return nil
+}
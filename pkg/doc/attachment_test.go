@@ -0,0 +1,107 @@
+package doc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAttachment(t *testing.T) {
+	content := []byte("0123456789abcdefghij!")
+	digest := sha256.Sum256(content)
+
+	var chunks []string
+	info, err := SplitAttachment(bytes.NewReader(content), 10, func(chunk int, data []byte) error {
+		assert.Equal(t, len(chunks), chunk)
+		chunks = append(chunks, string(data))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, []string{"0123456789", "abcdefghij", "!"}, chunks)
+	assert.Equal(t, &AttachmentInfo{Size: 21, ChunkSize: 10, Digest: hex.EncodeToString(digest[:])}, info)
+	assert.Equal(t, 3, info.Chunks())
+
+	verifier := NewAttachmentVerifier(info)
+	for _, chunk := range chunks {
+		if _, err := verifier.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assert.NoError(t, verifier.Verify())
+
+	verifier = NewAttachmentVerifier(info)
+	_, err = verifier.Write([]byte("012345678"))
+	assert.EqualError(t, err, "chunk #0 of attachment has invalid size 9")
+
+	verifier = NewAttachmentVerifier(info)
+	_, _ = verifier.Write([]byte("0123456789"))
+	assert.EqualError(t, verifier.Verify(), "attachment is incomplete: read 10 bytes of 21")
+
+	verifier = NewAttachmentVerifier(info)
+	for _, chunk := range []string{"0123456789", "abcdefghij", "?"} {
+		_, _ = verifier.Write([]byte(chunk))
+	}
+	assert.Contains(t, verifier.Verify().Error(), "attachment digest mismatch")
+
+	// Empty content has no chunks.
+	info, err = SplitAttachment(bytes.NewReader(nil), 10, func(chunk int, data []byte) error {
+		t.Fatalf("unexpected chunk #%d", chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, 0, info.Chunks())
+	assert.NoError(t, NewAttachmentVerifier(info).Verify())
+
+	_, err = SplitAttachment(bytes.NewReader(content), 0, nil)
+	assert.EqualError(t, err, "invalid chunk size 0")
+}
+
+func TestAttachmentProperties(t *testing.T) {
+	assert.Equal(t, "docID/report.pdf/attachment", AttachmentKey("docID", "report.pdf"))
+	assert.Equal(t, "docID/report.pdf/2/chunk", ChunkKey("docID", "report.pdf", 2))
+	assert.Equal(t, "docID/scans%2Fid.png/%attachment", AttachmentKey("docID", "scans/id.png"))
+
+	info := &AttachmentInfo{Size: 3, ChunkSize: 2, Digest: "digest", Indexes: []uint64{4, 5}}
+	attachment := PropertyAttachment("docID", "report.pdf", info)
+	assert.True(t, attachment.IsAttachment())
+	assert.True(t, PropertyChunk("docID", "scans/id.png", 0, []byte("data")).IsAttachment())
+	assert.False(t, PropertyEntry{KeyURI: "docID/attachment/string"}.IsAttachment())
+	assert.True(t, PropertyEntry{KeyURI: "docID/first%20name/%$attachment"}.IsAttachment())
+	assert.True(t, PropertyEntry{KeyURI: SaltedKey(attachment.KeyURI)}.IsAttachment())
+
+	parsed, err := ParseAttachment(attachment.Value, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, info, parsed)
+
+	_, err = ParseAttachment([]byte(`{"size":3,"chunkSize":2,"indexes":[4]}`), 6)
+	assert.EqualError(t, err, "invalid attachment: 2 chunks expected, got 1")
+
+	// Offsets are relative to the index of the attachment property.
+	parsed, err = ParseAttachment([]byte(`{"size":3,"chunkSize":2,"sha256":"digest","offsets":[2,1]}`), 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, info, parsed)
+
+	_, err = ParseAttachment([]byte(`{"size":3,"chunkSize":2,"offsets":[7,1]}`), 6)
+	assert.EqualError(t, err, "invalid attachment: invalid chunk offset 7")
+	_, err = ParseAttachment([]byte(`{"size":3,"chunkSize":2,"indexes":[4,5],"offsets":[2,1]}`), 6)
+	assert.EqualError(t, err, "invalid attachment: both indexes and offsets")
+
+	// Attachments are not part of the document's raw object.
+	properties := PropertyEntryList{
+		{KeyURI: "docID/name/string", Value: []byte("John")},
+		attachment,
+		PropertyChunk("docID", "report.pdf", 0, []byte("ab")),
+	}
+	assert.Equal(t, map[string]interface{}{"name": "John"}, PropertyListToRaw(properties))
+}
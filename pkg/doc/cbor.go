@@ -0,0 +1,412 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"unicode/utf8"
+)
+
+// CBOR major types.
+const (
+	cborUint byte = iota
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// CBOR additional information values with a special meaning.
+const (
+	cborFalse      = 20
+	cborTrue       = 21
+	cborNull       = 22
+	cborUndefined  = 23
+	cborFloat16    = 25
+	cborFloat32    = 26
+	cborFloat64    = 27
+	cborIndefinite = 31
+	cborBreak      = 0xff
+)
+
+// CBOR tags supported by the decoder.
+const (
+	cborTagDateTime     = 0
+	cborTagEpochTime    = 1
+	cborTagPosBignum    = 2
+	cborTagNegBignum    = 3
+	cborTagURI          = 32
+	cborTagSelfDescribe = 55799
+)
+
+// maxNestingDepth limits the nesting of arrays and maps of binary documents.
+const maxNestingDepth = 1000
+
+// cborCodec represents the CBOR format (RFC 8949). Byte strings are decoded as
+// their base64 encoding, as done by JSON, and big integers are supported through
+// the bignum tags. Date/time, epoch time, URI and self-described CBOR tags are
+// decoded as their content, which is a value of the JSON data model, while any
+// other tag is rejected, as its meaning would be lost. Map keys that are not
+// text strings are converted to their string representation.
+type cborCodec struct{}
+
+func (cborCodec) Decode(r io.Reader, opts ...Option) (interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &cborDecoder{r: bytes.NewReader(data), o: newOptions(opts)}
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	return value, trailingData(d.r)
+}
+
+func (cborCodec) Encode(rawObject interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeCBOR(&buf, rawObject); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// cborDecoder decodes CBOR data items to raw objects.
+type cborDecoder struct {
+	r *bytes.Reader
+	o *options
+}
+
+// readHead reads the initial byte of a data item, and its argument, if any.
+func (d *cborDecoder) readHead() (major, info byte, arg uint64, err error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return 0, 0, 0, io.ErrUnexpectedEOF
+	}
+	major, info = b>>5, b&0x1f
+
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		data, err := d.readN(1 << (info - 24))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, b := range data {
+			arg = arg<<8 | uint64(b)
+		}
+	case info == cborIndefinite && major != cborUint && major != cborNegInt && major != cborTag:
+	default:
+		return 0, 0, 0, fmt.Errorf("invalid CBOR additional information %d", info)
+	}
+	return major, info, arg, nil
+}
+
+// readN reads the next n bytes.
+func (d *cborDecoder) readN(n uint64) ([]byte, error) {
+	if n > uint64(d.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, n)
+	_, _ = d.r.Read(data)
+	return data, nil
+}
+
+// isBreak checks if the next byte ends an indefinite length item, consuming it
+// if so.
+func (d *cborDecoder) isBreak() (bool, error) {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return false, io.ErrUnexpectedEOF
+	}
+	if b == cborBreak {
+		return true, nil
+	}
+	return false, d.r.UnreadByte()
+}
+
+// readString reads the content of a byte or text string, given its major type
+// and argument, joining the chunks of indefinite length strings.
+func (d *cborDecoder) readString(major, info byte, arg uint64) ([]byte, error) {
+	if info != cborIndefinite {
+		return d.readN(arg)
+	}
+
+	var data []byte
+	for {
+		done, err := d.isBreak()
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return data, nil
+		}
+		chunkMajor, chunkInfo, chunkArg, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == cborIndefinite {
+			return nil, fmt.Errorf("invalid CBOR string chunk")
+		}
+		chunk, err := d.readN(chunkArg)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+}
+
+// decode decodes the next data item.
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxNestingDepth {
+		return nil, fmt.Errorf("maximum nesting depth exceeded")
+	}
+
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return uintValue(arg, d.o), nil
+	case cborNegInt:
+		if arg <= math.MaxInt64 {
+			return intValue(-1-int64(arg), d.o), nil
+		}
+		n := new(big.Int).SetUint64(arg)
+		return bigIntValue(n.Neg(n).Sub(n, big.NewInt(1)), d.o), nil
+	case cborBytes:
+		data, err := d.readString(major, info, arg)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	case cborText:
+		data, err := d.readString(major, info, arg)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("invalid UTF-8 text string")
+		}
+		return string(data), nil
+	case cborArray:
+		array := []interface{}{}
+		for i := uint64(0); info == cborIndefinite || i < arg; i++ {
+			if info == cborIndefinite {
+				if done, err := d.isBreak(); err != nil || done {
+					return array, err
+				}
+			}
+			element, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, element)
+		}
+		return array, nil
+	case cborMap:
+		object := newObjectFor(d.o)
+		members, _ := asObject(object)
+		for i := uint64(0); info == cborIndefinite || i < arg; i++ {
+			if info == cborIndefinite {
+				if done, err := d.isBreak(); err != nil || done {
+					return object, err
+				}
+			}
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			keyString, err := mapKey(key)
+			if err != nil {
+				return nil, err
+			}
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			members.Set(keyString, value)
+		}
+		return object, nil
+	case cborTag:
+		switch arg {
+		case cborTagPosBignum, cborTagNegBignum:
+			return d.decodeBignum(arg == cborTagNegBignum)
+		case cborTagDateTime, cborTagEpochTime, cborTagURI, cborTagSelfDescribe:
+			return d.decode(depth + 1)
+		}
+		return nil, fmt.Errorf("unsupported CBOR tag %d", arg)
+	}
+
+	switch info {
+	case cborFalse:
+		return false, nil
+	case cborTrue:
+		return true, nil
+	case cborNull, cborUndefined:
+		return nil, nil
+	case cborFloat16:
+		return floatValue(float16ToFloat64(uint16(arg)), d.o)
+	case cborFloat32:
+		return floatValue(float64(math.Float32frombits(uint32(arg))), d.o)
+	case cborFloat64:
+		return floatValue(math.Float64frombits(arg), d.o)
+	}
+	return nil, fmt.Errorf("unsupported CBOR simple value %d", arg)
+}
+
+// decodeBignum decodes the byte string content of a bignum tag.
+func (d *cborDecoder) decodeBignum(negative bool) (interface{}, error) {
+	major, info, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborBytes {
+		return nil, fmt.Errorf("invalid CBOR bignum")
+	}
+	data, err := d.readString(major, info, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	n := new(big.Int).SetBytes(data)
+	if negative {
+		n.Neg(n).Sub(n, big.NewInt(1))
+	}
+	return bigIntValue(n, d.o), nil
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision float.
+func float16ToFloat64(h uint16) float64 {
+	exponent := int(h>>10) & 0x1f
+	mantissa := float64(h & 0x3ff)
+
+	var f float64
+	switch exponent {
+	case 0:
+		f = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mantissa != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mantissa+1024, exponent-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
+
+// encodeCBOR encodes a raw object as a CBOR data item.
+func encodeCBOR(buf *bytes.Buffer, rawObject interface{}) error {
+	if object, ok := asObject(rawObject); ok {
+		writeCBORHead(buf, cborMap, uint64(object.Len()))
+		for _, key := range object.Keys() {
+			member, _ := object.Get(key)
+			writeCBORHead(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := encodeCBOR(buf, member); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch v := rawObject.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | cborNull)
+	case bool:
+		if v {
+			buf.WriteByte(cborSimple<<5 | cborTrue)
+		} else {
+			buf.WriteByte(cborSimple<<5 | cborFalse)
+		}
+	case string:
+		writeCBORHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case float64:
+		writeCBORNumber(buf, floatToNative(v))
+	case json.Number:
+		writeCBORNumber(buf, numberToNative(v))
+	case []interface{}:
+		writeCBORHead(buf, cborArray, uint64(len(v)))
+		for _, element := range v {
+			if err := encodeCBOR(buf, element); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value of type %T", rawObject)
+	}
+	return nil
+}
+
+// writeCBORNumber writes a native number in its most compact form.
+func writeCBORNumber(buf *bytes.Buffer, number interface{}) {
+	switch n := number.(type) {
+	case int64:
+		if n >= 0 {
+			writeCBORHead(buf, cborUint, uint64(n))
+		} else {
+			writeCBORHead(buf, cborNegInt, uint64(-1-n))
+		}
+	case uint64:
+		writeCBORHead(buf, cborUint, n)
+	case *big.Int:
+		tag := uint64(cborTagPosBignum)
+		if n.Sign() < 0 {
+			tag = cborTagNegBignum
+			n = new(big.Int).Neg(n)
+			n.Sub(n, big.NewInt(1))
+			if n.IsUint64() {
+				// Down to -2^64, negative integers have a native form.
+				writeCBORHead(buf, cborNegInt, n.Uint64())
+				return
+			}
+		}
+		data := n.Bytes()
+		writeCBORHead(buf, cborTag, tag)
+		writeCBORHead(buf, cborBytes, uint64(len(data)))
+		buf.Write(data)
+	case float64:
+		buf.WriteByte(cborSimple<<5 | cborFloat64)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(n))
+	}
+}
+
+// writeCBORHead writes the initial byte of a data item, and its argument, in
+// its shortest form.
+func writeCBORHead(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		_ = binary.Write(buf, binary.BigEndian, uint16(arg))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		_ = binary.Write(buf, binary.BigEndian, uint32(arg))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		_ = binary.Write(buf, binary.BigEndian, arg)
+	}
+}
@@ -0,0 +1,166 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Examples of RFC 8949, Appendix A.
+func TestCBORCodec_Decode(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{"00", `0`},
+		{"17", `23`},
+		{"1818", `24`},
+		{"1903e8", `1000`},
+		{"1b000000e8d4a51000", `1000000000000`},
+		{"1bffffffffffffffff", `18446744073709551615`},
+		{"c249010000000000000000", `18446744073709551616`},
+		{"3bffffffffffffffff", `-18446744073709551616`},
+		{"c349010000000000000000", `-18446744073709551617`},
+		{"20", `-1`},
+		{"3903e7", `-1000`},
+		{"f98000", `-0`},
+		{"f93e00", `1.5`},
+		{"f97bff", `65504`},
+		{"fa47c35000", `100000`},
+		{"fb7e37e43c8800759c", `1e+300`},
+		{"f90001", `5.960464477539063e-08`},
+		{"f9c400", `-4`},
+		{"fbc010666666666666", `-4.1`},
+		{"f4", `false`},
+		{"f5", `true`},
+		{"f6", `null`},
+		{"f7", `null`},
+		{"c074323031332d30332d32315432303a30343a30305a", `"2013-03-21T20:04:00Z"`},
+		{"c11a514b67b0", `1363896240`},
+		{"d82076687474703a2f2f7777772e6578616d706c652e636f6d", `"http://www.example.com"`},
+		{"d9d9f7a161610f", `{"a":15}`},
+		{"4401020304", `"AQIDBA=="`},
+		{"60", `""`},
+		{"62c3bc", `"ü"`},
+		{"80", `[]`},
+		{"8301820203820405", `[1,[2,3],[4,5]]`},
+		{"a0", `{}`},
+		{"a201020304", `{"1":2,"3":4}`},
+		{"a26161016162820203", `{"a":1,"b":[2,3]}`},
+		{"5f42010243030405ff", `"AQIDBAU="`},
+		{"7f657374726561646d696e67ff", `"streaming"`},
+		{"9fff", `[]`},
+		{"9f018202039f0405ffff", `[1,[2,3],[4,5]]`},
+		{"bf61610161629f0203ffff", `{"a":1,"b":[2,3]}`},
+	}
+
+	for _, tc := range testCases {
+		data, _ := hex.DecodeString(tc.data)
+		value, err := CBORCodec.Decode(bytes.NewReader(data), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.data, err)
+		}
+		got, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, tc.want, string(got), tc.data)
+	}
+
+	// Without exact numbers, numbers are decoded as float64.
+	value, err := CBORCodec.Decode(bytes.NewReader([]byte{0x19, 0x03, 0xe8}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, float64(1000), value)
+}
+
+func TestCBORCodec_DecodeErrors(t *testing.T) {
+	testCases := []struct {
+		data    string
+		wantErr string
+	}{
+		{"", "unexpected EOF"},
+		{"1903", "unexpected EOF"},
+		{"830102", "unexpected EOF"},
+		{"1c", "invalid CBOR additional information 28"},
+		{"1f", "invalid CBOR additional information 31"},
+		{"f97c00", "unsupported number +Inf"},
+		{"f0", "unsupported CBOR simple value 16"},
+		{"d74401020304", "unsupported CBOR tag 23"},
+		{"d818456449455446", "unsupported CBOR tag 24"},
+		{"62c328", "invalid UTF-8 text string"},
+		{"a18000", "unsupported map key of type []interface {}"},
+		{"0000", "invalid data after top-level value"},
+	}
+
+	for _, tc := range testCases {
+		data, _ := hex.DecodeString(tc.data)
+		_, err := CBORCodec.Decode(bytes.NewReader(data))
+		assert.EqualError(t, err, tc.wantErr, tc.data)
+	}
+}
+
+// Examples of RFC 8949, Appendix A, in their preferred serialization. Floats
+// are always encoded in double precision.
+func TestCBORCodec_Encode(t *testing.T) {
+	testCases := []struct {
+		json string
+		want string
+	}{
+		{`0`, "00"},
+		{`1`, "01"},
+		{`10`, "0a"},
+		{`23`, "17"},
+		{`24`, "1818"},
+		{`25`, "1819"},
+		{`100`, "1864"},
+		{`1000`, "1903e8"},
+		{`1000000`, "1a000f4240"},
+		{`-1`, "20"},
+		{`-10`, "29"},
+		{`-100`, "3863"},
+		{`-18446744073709551616`, "3bffffffffffffffff"},
+		{`1.1`, "fb3ff199999999999a"},
+		{`-4.1`, "fbc010666666666666"},
+		{`1e+300`, "fb7e37e43c8800759c"},
+		{`false`, "f4"},
+		{`true`, "f5"},
+		{`""`, "60"},
+		{`"a"`, "6161"},
+		{`"\"\\"`, "62225c"},
+		{`"\u00fc"`, "62c3bc"},
+		{`"\u6c34"`, "63e6b0b4"},
+		{`[]`, "80"},
+		{`[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25]`, "98190102030405060708090a0b0c0d0e0f101112131415161718181819"},
+		{`{}`, "a0"},
+		{`["a",{"b":"c"}]`, "826161a161626163"},
+		{`{"a":"A","b":"B","c":"C","d":"D","e":"E"}`, "a56161614161626142616361436164614461656145"},
+		{`1000000000000`, "1b000000e8d4a51000"},
+		{`18446744073709551615`, "1bffffffffffffffff"},
+		{`18446744073709551616`, "c249010000000000000000"},
+		{`-18446744073709551617`, "c349010000000000000000"},
+		{`-1000`, "3903e7"},
+		{`1.5`, "fb3ff8000000000000"},
+		{`"IETF"`, "6449455446"},
+		{`null`, "f6"},
+		{`[1,[2,3],[4,5]]`, "8301820203820405"},
+		{`{"b":[2,3],"a":1}`, "a26161016162820203"},
+	}
+
+	for _, tc := range testCases {
+		value, err := JSONCodec.Decode(strings.NewReader(tc.json), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := CBORCodec.Encode(value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.json, err)
+		}
+		assert.Equal(t, tc.want, hex.EncodeToString(got), tc.json)
+	}
+}
@@ -0,0 +1,270 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Codec converts documents between a wire format and raw document objects,
+// the flattened property model being independent of the format.
+type Codec interface {
+	// Decode decodes the document read from r to its raw object.
+	Decode(r io.Reader, opts ...Option) (interface{}, error)
+	// Encode encodes a raw document object.
+	Encode(rawObject interface{}) ([]byte, error)
+}
+
+var (
+	// JSONCodec encodes documents as indented JSON.
+	JSONCodec Codec = jsonCodec{}
+	// YAMLCodec encodes documents as YAML.
+	YAMLCodec Codec = yamlCodec{}
+	// CBORCodec encodes documents as CBOR (RFC 8949).
+	CBORCodec Codec = cborCodec{}
+	// MsgPackCodec encodes documents as MessagePack.
+	MsgPackCodec Codec = msgPackCodec{}
+)
+
+// codecs maps the supported format names to their codec.
+var codecs = map[string]Codec{
+	"json":    JSONCodec,
+	"yaml":    YAMLCodec,
+	"cbor":    CBORCodec,
+	"msgpack": MsgPackCodec,
+}
+
+// CodecByName returns the codec of a format given its name, being one of
+// "json", "yaml", "cbor" or "msgpack".
+func CodecByName(name string) (Codec, error) {
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown document format '%s'", name)
+	}
+	return codec, nil
+}
+
+// jsonCodec represents the JSON format.
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(r io.Reader, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+	return o.decode(o.newDecoder(r))
+}
+
+func (jsonCodec) Encode(rawObject interface{}) ([]byte, error) {
+	return json.MarshalIndent(rawObject, "", "  ")
+}
+
+// yamlCodec represents the YAML format. Mapping keys that are not strings are
+// converted to their string representation.
+type yamlCodec struct{}
+
+func (yamlCodec) Decode(r io.Reader, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if o.orderedKeys {
+		// Mappings are only decoded in order when the document is one.
+		var mapping yaml.MapSlice
+		if err := yaml.Unmarshal(data, &mapping); err == nil && mapping != nil {
+			value = mapping
+		}
+	}
+	if value == nil {
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	return fromYAML(value, o)
+}
+
+func (yamlCodec) Encode(rawObject interface{}) ([]byte, error) {
+	return yaml.Marshal(toYAML(rawObject))
+}
+
+// fromYAML converts a decoded YAML value to its raw object.
+func fromYAML(value interface{}, o *options) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return v, nil
+	case int:
+		return intValue(int64(v), o), nil
+	case int64:
+		return intValue(v, o), nil
+	case uint64:
+		return uintValue(v, o), nil
+	case float64:
+		return floatValue(v, o)
+	case yaml.MapSlice:
+		object := NewObject()
+		for _, item := range v {
+			member, err := fromYAML(item.Value, o)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(fmt.Sprint(item.Key), member)
+		}
+		return object, nil
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			member, err := fromYAML(item, o)
+			if err != nil {
+				return nil, err
+			}
+			object[fmt.Sprint(key)] = member
+		}
+		return object, nil
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, item := range v {
+			element, err := fromYAML(item, o)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = element
+		}
+		return array, nil
+	}
+
+	return nil, fmt.Errorf("unsupported YAML value of type %T", value)
+}
+
+// toYAML converts a raw object to a value encoded as YAML, where ordered
+// objects keep the order of their members.
+func toYAML(rawObject interface{}) interface{} {
+	switch v := rawObject.(type) {
+	case json.Number:
+		// Integers beyond 64 bits are not supported by YAML decoders.
+		if b, ok := numberToNative(v).(*big.Int); ok {
+			f, _ := new(big.Float).SetInt(b).Float64()
+			return f
+		}
+		return numberToNative(v)
+	case *Object:
+		mapping := make(yaml.MapSlice, 0, v.Len())
+		for _, key := range v.Keys() {
+			member, _ := v.Get(key)
+			mapping = append(mapping, yaml.MapItem{Key: key, Value: toYAML(member)})
+		}
+		return mapping
+	case map[string]interface{}:
+		mapping := make(map[string]interface{}, len(v))
+		for key, member := range v {
+			mapping[key] = toYAML(member)
+		}
+		return mapping
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, element := range v {
+			array[i] = toYAML(element)
+		}
+		return array
+	}
+	return rawObject
+}
+
+// newObjectFor returns an empty raw object, being ordered in order-preserving
+// mode.
+func newObjectFor(o *options) interface{} {
+	if o.orderedKeys {
+		return NewObject()
+	}
+	return map[string]interface{}{}
+}
+
+// mapKey converts a decoded map key to an object key.
+func mapKey(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case string:
+		return k, nil
+	case nil, bool, float64, json.Number:
+		data, err := json.Marshal(k)
+		return string(data), err
+	}
+	return "", fmt.Errorf("unsupported map key of type %T", key)
+}
+
+// intValue returns the raw number of a decoded integer.
+func intValue(n int64, o *options) interface{} {
+	if o.exactNumbers {
+		return json.Number(strconv.FormatInt(n, 10))
+	}
+	return float64(n)
+}
+
+// uintValue returns the raw number of a decoded unsigned integer.
+func uintValue(n uint64, o *options) interface{} {
+	if o.exactNumbers {
+		return json.Number(strconv.FormatUint(n, 10))
+	}
+	return float64(n)
+}
+
+// bigIntValue returns the raw number of a decoded big integer.
+func bigIntValue(n *big.Int, o *options) interface{} {
+	if o.exactNumbers {
+		return json.Number(n.String())
+	}
+	f, _ := new(big.Float).SetInt(n).Float64()
+	return f
+}
+
+// floatValue returns the raw number of a decoded float, which must be finite
+// as JSON has no representation for infinities and NaN.
+func floatValue(f float64, o *options) (interface{}, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("unsupported number %v", f)
+	}
+	if o.exactNumbers {
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	}
+	return f, nil
+}
+
+// numberToNative converts a raw number to the closest native number: an int64
+// or uint64 for integers, a big.Int for larger ones, and a float64 otherwise.
+func numberToNative(n json.Number) interface{} {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return u
+	}
+	if b, ok := new(big.Int).SetString(n.String(), 10); ok {
+		return b
+	}
+	f, _ := strconv.ParseFloat(n.String(), 64)
+	return f
+}
+
+// floatToNative converts a float64 holding an integer value to an int64, so
+// that binary formats encode it in its compact integer form.
+func floatToNative(f float64) interface{} {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 && !(f == 0 && math.Signbit(f)) {
+		return int64(f)
+	}
+	return f
+}
+
+// trailingData checks that a binary document has no data after its value.
+func trailingData(r *bytes.Reader) error {
+	if r.Len() > 0 {
+		return fmt.Errorf("invalid data after top-level value")
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	jsonPayload := `{"name":"John","age":30,"price":19.99,"tags":["a",false,null],"address":{"zip":"10001"},"meta":{},"list":[]}`
+
+	for _, name := range []string{"json", "yaml", "cbor", "msgpack"} {
+		codec, err := CodecByName(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The document converted to the codec's format results in the same
+		// properties than the JSON one.
+		rawObject, err := JSONCodec.Decode(strings.NewReader(jsonPayload))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		payload, err := codec.Encode(rawObject)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		propertyList, err := DecodeToPropertyList("docID", bytes.NewReader(payload), codec)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		wantPropertyList, err := RawToPropertyList("docID", strings.NewReader(jsonPayload))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.ElementsMatch(t, wantPropertyList, propertyList, name)
+
+		gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, `{"address":{"zip":"10001"},"age":30,"list":[],"meta":{},"name":"John","price":19.99,"tags":["a",false,null]}`, string(gotPayload), name)
+	}
+
+	_, err := CodecByName("xml")
+	assert.EqualError(t, err, "unknown document format 'xml'")
+}
+
+// randomRawValue returns a random raw JSON value, with exact numbers, nested up
+// to the given depth. Integers beyond 64 bits are only returned if requested.
+func randomRawValue(rnd *rand.Rand, depth int, bigIntegers bool) interface{} {
+	kind := rnd.Intn(9)
+	if depth == 0 {
+		kind = rnd.Intn(6)
+	}
+
+	switch kind {
+	case 0:
+		return nil
+	case 1:
+		return rnd.Intn(2) == 0
+	case 2:
+		return randomString(rnd)
+	case 3:
+		// Integers of every size, including those beyond 64 bits.
+		n := rnd.Int63() >> uint(rnd.Intn(63))
+		if rnd.Intn(2) == 0 {
+			n = -n
+		}
+		return json.Number(strconv.FormatInt(n, 10))
+	case 4:
+		if !bigIntegers {
+			return json.Number(strconv.FormatUint(rnd.Uint64(), 10))
+		}
+		return json.Number(fmt.Sprintf("-%d%d", rnd.Int63(), rnd.Int63()))
+	case 5:
+		f := rnd.NormFloat64() * math.Pow(10, float64(rnd.Intn(40)-20))
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	case 6, 7:
+		array := make([]interface{}, randomLength(rnd))
+		for i := range array {
+			array[i] = randomRawValue(rnd, depth-1, bigIntegers)
+		}
+		return array
+	default:
+		object := map[string]interface{}{}
+		for i := randomLength(rnd); i > 0; i-- {
+			object[randomString(rnd)] = randomRawValue(rnd, depth-1, bigIntegers)
+		}
+		return object
+	}
+}
+
+// randomLength returns a random length of arrays and objects, around the length
+// limits of the formats of the binary codecs.
+func randomLength(rnd *rand.Rand) int {
+	return []int{0, 1, 2, 15, 16, 24}[rnd.Intn(6)]
+}
+
+// randomString returns a random string, around the length limits of the formats
+// of the binary codecs, either ASCII or with multi-byte characters.
+func randomString(rnd *rand.Rand) string {
+	alphabet := []rune("ab/ %[]0\"")
+	if rnd.Intn(4) == 0 {
+		alphabet = []rune("a\u00fc\u6c34\U0001f600")
+	}
+	length := []int{0, 1, 23, 24, 31, 32, 255, 256}[rnd.Intn(8)]
+	if rnd.Intn(50) == 0 {
+		length = 65536
+	}
+	runes := make([]rune, length)
+	for i := range runes {
+		runes[i] = alphabet[rnd.Intn(len(alphabet))]
+	}
+	return string(runes)
+}
+
+// Random documents encoded by the binary codecs are decoded as is, while their
+// truncated or corrupted encodings are rejected or decoded without panicking.
+func TestCodec_RandomRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, codec := range []Codec{CBORCodec, MsgPackCodec} {
+		for i := 0; i < 200; i++ {
+			rawObject := randomRawValue(rnd, 3, codec == CBORCodec)
+			wantPayload, err := json.Marshal(rawObject)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			payload, err := codec.Encode(rawObject)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", wantPayload, err)
+			}
+			decoded, err := codec.Decode(bytes.NewReader(payload), WithExactNumbers())
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", wantPayload, err)
+			}
+			gotPayload, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.Equal(t, string(wantPayload), string(gotPayload))
+
+			_, err = codec.Decode(bytes.NewReader(payload[:rnd.Intn(len(payload))]))
+			assert.Error(t, err, string(wantPayload))
+
+			corrupted := append([]byte(nil), payload...)
+			corrupted[rnd.Intn(len(corrupted))] ^= byte(1 + rnd.Intn(255))
+			_, _ = codec.Decode(bytes.NewReader(corrupted))
+		}
+	}
+}
+
+func TestCodec_OrderedKeysAndExactNumbers(t *testing.T) {
+	jsonPayload := `{"name":"John","id":18446744073709551615,"big":-123456789012345678901234567890,"address":{"zip":"10001","city":"New York"}}`
+
+	for _, codec := range []Codec{JSONCodec, YAMLCodec, CBORCodec} {
+		rawObject, err := JSONCodec.Decode(strings.NewReader(jsonPayload), WithOrderedKeys(), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		payload, err := codec.Encode(rawObject)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		rawObject, err = codec.Decode(bytes.NewReader(payload), WithOrderedKeys(), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		gotPayload, err := json.Marshal(rawObject)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if codec == YAMLCodec {
+			// Integers beyond 64 bits are not supported by YAML.
+			assert.Equal(t, `{"name":"John","id":18446744073709551615,"big":-1.2345678901234568e+29,"address":{"zip":"10001","city":"New York"}}`, string(gotPayload))
+			continue
+		}
+		assert.Equal(t, jsonPayload, string(gotPayload))
+	}
+}
+
+func TestYAMLCodec(t *testing.T) {
+	yamlPayload := `
+name: John
+age: 30
+1: one
+active: true
+tags:
+  - a
+  - null
+`
+	rawObject, err := YAMLCodec.Decode(strings.NewReader(yamlPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, map[string]interface{}{
+		"name":   "John",
+		"age":    float64(30),
+		"1":      "one",
+		"active": true,
+		"tags":   []interface{}{"a", nil},
+	}, rawObject)
+
+	payload, err := YAMLCodec.Encode(rawObject)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `"1": one
+active: true
+age: 30
+name: John
+tags:
+- a
+- null
+`, string(payload))
+
+	_, err = YAMLCodec.Decode(strings.NewReader(`value: .inf`))
+	assert.EqualError(t, err, "unsupported number +Inf")
+
+	_, err = DecodeToPropertyList("docID", strings.NewReader(`[a`), YAMLCodec)
+	assert.Error(t, err)
+}
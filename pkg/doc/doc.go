@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
 	"regexp"
@@ -20,24 +21,86 @@ type PropertyEntry struct {
 	Value  []byte
 }
 
-// DissectKeyURI returns the underlying parts of a given key format.
+// DissectKeyURI returns the underlying parts of a given key format: the
+// document ID, the path segments, with object keys unescaped, and the type.
 func (p PropertyEntry) DissectKeyURI() (string, []string, string) {
+	docID, path, vType := p.dissectPath()
+
+	keys := make([]string, len(path))
+	for i, segment := range path {
+		keys[i] = segment.key
+	}
+
+	return docID, keys, vType
+}
+
+// pathSegment represents a segment of the path of a property: either the key of
+// an object member, or an array element.
+type pathSegment struct {
+	key      string // Unescaped object key, or array element format.
+	isArray  bool
+	index    int // Index of the array element.
+	capacity int // Capacity of the array, only part of the format of earlier documents.
+}
+
+// keyType returns the type of a given key format, without the markers of
+// escaped keys and salted values.
+func keyType(keyURI string) string {
+	vType := strings.TrimPrefix(keyURI[strings.LastIndex(keyURI, "/")+1:], escapedKeyMarker)
+	return strings.TrimPrefix(vType, saltedKeyMarker)
+}
+
+// dissectPath returns the document ID, the path segments and the type of a
+// given key format.
+func (p PropertyEntry) dissectPath() (string, []pathSegment, string) {
+	return p.dissectPathIn(nil)
+}
+
+// dissectPathIn returns the parts of a given key format as done by dissectPath,
+// given the key prefixes of the arrays of its document, i.e. the keys of their
+// length properties without the type. Path segments of the "[%d]" format are
+// only array elements if their parent is one of those arrays, as documents
+// stored before arrays had a length property may have object keys of that same
+// format. Every such segment is an array element if no prefixes are given.
+func (p PropertyEntry) dissectPathIn(arrays map[string]bool) (string, []pathSegment, string) {
 	if !hasKeyFormat(p.KeyURI) {
 		panic(fmt.Sprintf("property '%s' has invalid format", p.KeyURI))
 	}
 	keys := strings.Split(p.KeyURI, "/")
 	lastElemIdx := len(keys) - 1
 
-	return keys[0], keys[1:lastElemIdx], keys[lastElemIdx]
+	escaped := strings.HasPrefix(keys[lastElemIdx], escapedKeyMarker)
+	vType := keyType(p.KeyURI)
+
+	path := make([]pathSegment, 0, lastElemIdx-1)
+	for i, key := range keys[1:lastElemIdx] {
+		// Array elements are never escaped, while escaped object keys never
+		// have the array format.
+		if hasArrayFormat(key) {
+			index, capacity := splitArrayFormat(key)
+			if capacity > 0 || arrays == nil || arrays[strings.Join(keys[:i+1], "/")] {
+				path = append(path, pathSegment{key: key, isArray: true, index: index, capacity: capacity})
+				continue
+			}
+		}
+		if escaped {
+			key = UnescapeKey(key)
+		}
+		path = append(path, pathSegment{key: key})
+	}
+
+	return keys[0], path, vType
 }
 
-// Key format: <docID>/<s>/(<s>/<s>)*/<type>
-var keyRegExp = regexp.MustCompile(`^\S+\/\S+(\/\S+)*\/(?:nil|string|bool|float64)$`)
+// Key format: <docID>/(<s>/<s>/...)/<type>
+// Where the path is empty for documents that are a single scalar value or an
+// empty container, and the type is prefixed by '%' if the object keys of the
+// path are escaped, followed by '$' if the value is salted. Attachments have
+// their own types, outside of the document's raw object.
+var keyRegExp = regexp.MustCompile(`^[^/\s]+(\/[^/\s]*)*\/%?\$?(?:nil|string|bool|float64|number|object|array|attachment|chunk)$`)
 
-// hasKeyFormat checks if the current node of the path describes and array element.
+// hasKeyFormat checks if a given key has the property key format.
 func hasKeyFormat(s string) bool {
-	// Checks for Arrays definitions of the format "[%d.%d]" where the first
-	// parameter describes the current index and the second the total capacity.
 	return keyRegExp.MatchString(s)
 }
 
@@ -57,9 +120,10 @@ func (p PropertyEntryList) Less(i, j int) bool {
 
 // PropertyHash represents a hash sum of a given property.
 type PropertyHash struct {
-	Index uint64 // Index of property DB entry.
-	Key   string
-	Hash  []byte // Hash of property DB entry.
+	Index   uint64 // Index of property DB entry.
+	Key     string
+	Hash    []byte // Hash of property DB entry.
+	Content []byte // Hash of property key and value, regardless of its index.
 }
 
 // CreatePropertyHash returns a property hash of a given key-value pair.
@@ -67,12 +131,28 @@ func CreatePropertyHash(index uint64, key, value []byte) *PropertyHash {
 	digest := immuapi.Digest(index, key, value)
 
 	return &PropertyHash{
-		Index: index,
-		Key:   string(key),
-		Hash:  digest[:],
+		Index:   index,
+		Key:     string(key),
+		Hash:    digest[:],
+		Content: ContentHash(key, value),
 	}
 }
 
+// ContentHash returns the hash of the key and value of a property, regardless
+// of the index of its DB entry. Unlike the hash of the DB entry, it is known
+// before the property is written.
+func ContentHash(key, value []byte) []byte {
+	var keyLength [8]byte
+	binary.BigEndian.PutUint64(keyLength[:], uint64(len(key)))
+
+	contentSum := sha256.New()
+	_, _ = contentSum.Write(keyLength[:])
+	_, _ = contentSum.Write(key)
+	_, _ = contentSum.Write(value)
+
+	return contentSum.Sum(nil)
+}
+
 // Properties defined a list of property index hashes pairs.
 type PropertyHashList []*PropertyHash
 
@@ -100,6 +180,18 @@ func (p PropertyHashList) Hash() string {
 	return hex.EncodeToString(sum)
 }
 
+// ContentHash returns the hash of the content hashes of a property hash list,
+// which, unlike its global hash, does not depend on the indexes of the
+// properties, only on their order.
+func (p PropertyHashList) ContentHash() string {
+	contentSum := sha256.New()
+	for _, hash := range p {
+		_, _ = contentSum.Write(hash.Content)
+	}
+
+	return hex.EncodeToString(contentSum.Sum(nil))
+}
+
 // Indexes returns the associated indexes of a given property hash list.
 func (p PropertyHashList) Indexes() []uint64 {
 	indexes := make([]uint64, len(p))
@@ -118,6 +210,35 @@ func PropertyNil(keys []string) PropertyEntry {
 	}
 }
 
+// PropertyEmptyObject converts a property path with an empty object value to a
+// PropertyEntry.
+func PropertyEmptyObject(keys []string) PropertyEntry {
+	return PropertyEntry{
+		KeyURI: strings.Join(keys, "/") + "/object",
+		Value:  nil,
+	}
+}
+
+// PropertyObject converts an ordered object path and the keys of its members,
+// in order, to a PropertyEntry. The object's members are properties of their
+// own.
+func PropertyObject(keys []string, memberKeys []string) PropertyEntry {
+	value, _ := json.Marshal(memberKeys)
+	return PropertyEntry{
+		KeyURI: strings.Join(keys, "/") + "/object",
+		Value:  value,
+	}
+}
+
+// PropertyArray converts an array path and its length to a PropertyEntry. The
+// array's elements are properties of their own.
+func PropertyArray(keys []string, length int) PropertyEntry {
+	return PropertyEntry{
+		KeyURI: strings.Join(keys, "/") + "/array",
+		Value:  []byte(strconv.Itoa(length)),
+	}
+}
+
 // PropertyString converts a property path with string value to a PropertyEntry.
 func PropertyString(keys []string, value string) PropertyEntry {
 	return PropertyEntry{
@@ -142,6 +263,15 @@ func PropertyFloat64(keys []string, value float64) PropertyEntry {
 	}
 }
 
+// PropertyNumber converts a property path with a number value, decoded as
+// json.Number, to a PropertyEntry holding its exact decimal text.
+func PropertyNumber(keys []string, value json.Number) PropertyEntry {
+	return PropertyEntry{
+		KeyURI: strings.Join(keys, "/") + "/number",
+		Value:  []byte(value.String()),
+	}
+}
+
 // Float64ToBinary marshals a float64 to its binary representation.
 func Float64ToBinary(v float64) []byte {
 	var buf [8]byte
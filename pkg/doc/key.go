@@ -0,0 +1,77 @@
+package doc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// escapedKeyMarker prefixes the type of the property keys whose object keys
+// are escaped. Keys without the marker, such as the ones of documents stored
+// before escaping was introduced, are read as is.
+const escapedKeyMarker = "%"
+
+// EscapeKey escapes an object key so that it can be used as a segment of a
+// property key, returning whether escaping was needed. The escape character
+// '%', the path separator '/', ASCII whitespace and control characters are
+// percent-encoded, as well as the leading '[' of keys that would otherwise be
+// read as array elements. Any other character, Unicode included, is kept.
+func EscapeKey(key string) (string, bool) {
+	if !needsEscaping(key) {
+		return key, false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '%' || c == '/' || c <= ' ' || c == 0x7f || (i == 0 && c == '[') {
+			_, _ = fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String(), true
+}
+
+// UnescapeKey reverts the escaping of an object key done by EscapeKey.
+// Malformed escape sequences are kept as is.
+func UnescapeKey(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// needsEscaping checks if an object key can not be used as is as a segment of
+// a property key.
+func needsEscaping(key string) bool {
+	if hasArrayFormat(key) {
+		return true
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c == '%' || c == '/' || c <= ' ' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// markEscapedKey marks a property key as having escaped object keys.
+func markEscapedKey(keyURI string) string {
+	typeIdx := strings.LastIndex(keyURI, "/") + 1
+	return keyURI[:typeIdx] + escapedKeyMarker + keyURI[typeIdx:]
+}
@@ -0,0 +1,98 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeKey(t *testing.T) {
+	tests := map[string]struct {
+		key        string
+		expKey     string
+		expEscaped bool
+	}{
+		"Plain key":        {key: "name", expKey: "name", expEscaped: false},
+		"Unicode key":      {key: "名前", expKey: "名前", expEscaped: false},
+		"Empty key":        {key: "", expKey: "", expEscaped: false},
+		"Bracket key":      {key: "[name]", expKey: "[name]", expEscaped: false},
+		"Slash key":        {key: "a/b", expKey: "a%2Fb", expEscaped: true},
+		"Whitespace key":   {key: "first name\t", expKey: "first%20name%09", expEscaped: true},
+		"Percent key":      {key: "100%", expKey: "100%25", expEscaped: true},
+		"Array format key": {key: "[1]", expKey: "%5B1]", expEscaped: true},
+		"Legacy array key": {key: "[1.2]", expKey: "%5B1.2]", expEscaped: true},
+		"Control key":      {key: "a\x00\x7fb", expKey: "a%00%7Fb", expEscaped: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotKey, gotEscaped := EscapeKey(test.key)
+			assert.Equal(t, test.expKey, gotKey)
+			assert.Equal(t, test.expEscaped, gotEscaped)
+			assert.Equal(t, test.key, UnescapeKey(gotKey))
+		})
+	}
+}
+
+func TestUnescapeKey_Malformed(t *testing.T) {
+	assert.Equal(t, "100%", UnescapeKey("100%"))
+	assert.Equal(t, "%zz%2", UnescapeKey("%zz%2"))
+}
+
+func TestPropertyList_EscapedKeys(t *testing.T) {
+	jsonPayload := []byte(`{
+		"a/b": "slash",
+		"first name": "space",
+		"": "empty",
+		"[1.2]": "array format",
+		"100%": "percent",
+		"名前": "unicode",
+		"nested": {"x y": [{"/": true}, null], "plain": 1}
+	}`)
+
+	propertyList, err := RawToPropertyList("docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Keys without escaped object keys are left untouched.
+	assert.ElementsMatch(t, PropertyEntryList{
+		{KeyURI: "docID/a%2Fb/%string", Value: []byte("slash")},
+		{KeyURI: "docID/first%20name/%string", Value: []byte("space")},
+		{KeyURI: "docID//string", Value: []byte("empty")},
+		{KeyURI: "docID/%5B1.2]/%string", Value: []byte("array format")},
+		{KeyURI: "docID/100%25/%string", Value: []byte("percent")},
+		{KeyURI: "docID/名前/string", Value: []byte("unicode")},
+		{KeyURI: "docID/nested/x%20y/[0]/%2F/%bool", Value: []byte("true")},
+		{KeyURI: "docID/nested/x%20y/[1]/%nil", Value: nil},
+		{KeyURI: "docID/nested/plain/float64", Value: Float64ToBinary(1)},
+		{KeyURI: "docID/nested/x%20y/%array", Value: []byte("2")},
+	}, propertyList)
+
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, string(jsonPayload), string(gotPayload))
+
+	docID, keys, vType := PropertyEntry{KeyURI: "docID/x%20y/[0.2]/%5B1.2]/%bool"}.DissectKeyURI()
+	assert.Equal(t, "docID", docID)
+	assert.Equal(t, []string{"x y", "[0.2]", "[1.2]"}, keys)
+	assert.Equal(t, "bool", vType)
+}
+
+func TestPropertyListToRaw_UnescapedKeys(t *testing.T) {
+	// Keys stored before escaping was introduced are read as is.
+	propertyList := PropertyEntryList{
+		{KeyURI: "docID/100%25/string", Value: []byte("percent")},
+		{KeyURI: "docID/a/%/float64", Value: Float64ToBinary(1)},
+	}
+
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{"100%25": "percent", "a": {"%": 1}}`, string(gotPayload))
+}
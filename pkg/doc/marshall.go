@@ -9,48 +9,91 @@ import (
 
 // RawToPropertyList creates the property list for given document provided
 // a reader to the raw payload.
-func RawToPropertyList(docID string, r io.Reader) (PropertyEntryList, error) {
-	var docMap interface{}
-	if err := json.NewDecoder(r).Decode(&docMap); err != nil {
+func RawToPropertyList(docID string, r io.Reader, opts ...Option) (PropertyEntryList, error) {
+	return DecodeToPropertyList(docID, r, JSONCodec, opts...)
+}
+
+// DecodeToPropertyList creates the property list for given document provided
+// a reader to its payload, encoded with the given codec.
+func DecodeToPropertyList(docID string, r io.Reader, codec Codec, opts ...Option) (PropertyEntryList, error) {
+	rawObject, err := codec.Decode(r, opts...)
+	if err != nil {
 		return nil, fmt.Errorf("unable to unmarshall payload: %v", err)
 	}
 
-	return rawToPropertyList([]string{docID}, docMap), nil
+	return rawToPropertyList([]string{docID}, false, rawObject), nil
+}
+
+// ObjectToPropertyList creates the property list for given document provided
+// its already decoded raw object.
+func ObjectToPropertyList(docID string, rawObject interface{}) PropertyEntryList {
+	return rawToPropertyList([]string{docID}, false, rawObject)
 }
 
 // rawToPropertyList recursively transverses the raw object tree, building a
 // list of property entries for every leaf of said tree. Each property contains
 // Key-Value pair. The Key, describes a path from the root to the leaf, and the
-// Value is the leaf's value.
-func rawToPropertyList(keys []string, value interface{}) PropertyEntryList {
+// Value is the leaf's value. Object keys are escaped, the Key being marked as
+// such, if any of them requires it.
+func rawToPropertyList(keys []string, escaped bool, value interface{}) PropertyEntryList {
 	list := PropertyEntryList{}
 
+	var entry PropertyEntry
+
 	// https://www.w3schools.com/js/js_json_datatypes.asp
 	switch v := value.(type) {
 	case nil:
-		list = append(list, PropertyNil(keys))
+		entry = PropertyNil(keys)
 	case string:
-		list = append(list, PropertyString(keys, v))
+		entry = PropertyString(keys, v)
 	case bool:
-		list = append(list, PropertyBool(keys, v))
+		entry = PropertyBool(keys, v)
 	case float64:
-		list = append(list, PropertyFloat64(keys, v))
+		entry = PropertyFloat64(keys, v)
+	case json.Number:
+		entry = PropertyNumber(keys, v)
 	case map[string]interface{}:
+		// Empty objects are kept as leaves of their own.
+		if len(v) == 0 {
+			entry = PropertyEmptyObject(keys)
+			break
+		}
 		for key, value := range v {
-			keys = append(keys, key)
-			list = append(list, rawToPropertyList(keys, value)...)
+			escapedKey, isEscaped := EscapeKey(key)
+			keys = append(keys, escapedKey)
+			list = append(list, rawToPropertyList(keys, escaped || isEscaped, value)...)
+			removeLastElement(&keys)
+		}
+		return list
+	case *Object:
+		// The order of the members is a property of its own, so that it can be
+		// restored when reading the document.
+		entry = PropertyObject(keys, v.Keys())
+		for _, key := range v.Keys() {
+			value, _ := v.Get(key)
+			escapedKey, isEscaped := EscapeKey(key)
+			keys = append(keys, escapedKey)
+			list = append(list, rawToPropertyList(keys, escaped || isEscaped, value)...)
 			removeLastElement(&keys)
 		}
 	case []interface{}:
-		vLen := len(v)
+		// The array's length is a property of its own, so that its elements'
+		// keys only depend on their index.
+		entry = PropertyArray(keys, len(v))
 		for idx, arrElem := range v {
-			keys = append(keys, "["+strconv.Itoa(idx)+"."+strconv.Itoa(vLen)+"]")
-			list = append(list, rawToPropertyList(keys, arrElem)...)
+			keys = append(keys, "["+strconv.Itoa(idx)+"]")
+			list = append(list, rawToPropertyList(keys, escaped, arrElem)...)
 			removeLastElement(&keys)
 		}
+	default:
+		return list
+	}
+
+	if escaped {
+		entry.KeyURI = markEscapedKey(entry.KeyURI)
 	}
 
-	return list
+	return append(list, entry)
 }
 
 // removeLastElement removes the last object of a non-empty slice of strings.
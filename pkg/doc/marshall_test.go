@@ -33,6 +33,82 @@ var testCases = map[string]struct {
 			{KeyURI: "objectID/random/nil", Value: nil},
 		},
 	},
+	"Transforms empty objects and arrays": {
+		prefix: "objectID",
+		jsonPayload: []byte(`{
+			"tags": [],
+			"meta": {},
+			"matrix": [[], [{}]],
+			"nested": {"list": [], "name": "John"}
+		}`),
+		propertyList: PropertyEntryList{
+			{KeyURI: "objectID/tags/array", Value: []byte("0")},
+			{KeyURI: "objectID/meta/object", Value: nil},
+			{KeyURI: "objectID/matrix/[0]/array", Value: []byte("0")},
+			{KeyURI: "objectID/matrix/[1]/[0]/object", Value: nil},
+			{KeyURI: "objectID/nested/list/array", Value: []byte("0")},
+			{KeyURI: "objectID/nested/name/string", Value: []byte("John")},
+			{KeyURI: "objectID/matrix/array", Value: []byte("2")},
+			{KeyURI: "objectID/matrix/[1]/array", Value: []byte("1")},
+		},
+	},
+	"Transforms top-level string": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`"John"`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/string", Value: []byte("John")}},
+	},
+	"Transforms top-level number": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`30`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/float64", Value: Float64ToBinary(30)}},
+	},
+	"Transforms top-level boolean": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`false`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/bool", Value: []byte("false")}},
+	},
+	"Transforms top-level null": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`null`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/nil", Value: nil}},
+	},
+	"Transforms top-level empty object": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`{}`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/object", Value: nil}},
+	},
+	"Transforms top-level empty array": {
+		prefix:       "objectID",
+		jsonPayload:  []byte(`[]`),
+		propertyList: PropertyEntryList{{KeyURI: "objectID/array", Value: []byte("0")}},
+	},
+	"Transforms top-level array of scalars": {
+		prefix:      "objectID",
+		jsonPayload: []byte(`["a", 1, true, null]`),
+		propertyList: PropertyEntryList{
+			{KeyURI: "objectID/[0]/string", Value: []byte("a")},
+			{KeyURI: "objectID/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[2]/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[3]/nil", Value: nil},
+			{KeyURI: "objectID/array", Value: []byte("4")},
+		},
+	},
+	"Transforms top-level nested arrays": {
+		prefix:      "objectID",
+		jsonPayload: []byte(`[[1, 2], [], [[3], ["b"]]]`),
+		propertyList: PropertyEntryList{
+			{KeyURI: "objectID/[0]/[0]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[0]/[1]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[1]/array", Value: []byte("0")},
+			{KeyURI: "objectID/[2]/[0]/[0]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[2]/[1]/[0]/string", Value: []byte("b")},
+			{KeyURI: "objectID/array", Value: []byte("3")},
+			{KeyURI: "objectID/[0]/array", Value: []byte("2")},
+			{KeyURI: "objectID/[2]/array", Value: []byte("2")},
+			{KeyURI: "objectID/[2]/[0]/array", Value: []byte("1")},
+			{KeyURI: "objectID/[2]/[1]/array", Value: []byte("1")},
+		},
+	},
 	"Transforms nested objects #1": {
 		prefix: "objectID",
 		jsonPayload: []byte(`{
@@ -81,19 +157,21 @@ var testCases = map[string]struct {
 				}
 			}`),
 		propertyList: PropertyEntryList{
-			{KeyURI: "objectID/tags/[0.6]/string", Value: []byte("tag1")},
-			{KeyURI: "objectID/tags/[1.6]/string", Value: []byte("tag2")},
-			{KeyURI: "objectID/tags/[2.6]/string", Value: []byte("tag3")},
-			{KeyURI: "objectID/tags/[3.6]/string", Value: []byte("tag4")},
-			{KeyURI: "objectID/tags/[4.6]/string", Value: []byte("tag5")},
-			{KeyURI: "objectID/tags/[5.6]/string", Value: []byte("tag6")},
-			{KeyURI: "objectID/nested/tags/[0.6]/string", Value: []byte("tag7")},
-			{KeyURI: "objectID/nested/tags/[1.6]/string", Value: []byte("tag8")},
-			{KeyURI: "objectID/nested/tags/[2.6]/string", Value: []byte("tag9")},
-			{KeyURI: "objectID/nested/tags/[3.6]/string", Value: []byte("tag10")},
-			{KeyURI: "objectID/nested/tags/[4.6]/string", Value: []byte("tag11")},
-			{KeyURI: "objectID/nested/tags/[5.6]/string", Value: []byte("tag12")},
+			{KeyURI: "objectID/tags/[0]/string", Value: []byte("tag1")},
+			{KeyURI: "objectID/tags/[1]/string", Value: []byte("tag2")},
+			{KeyURI: "objectID/tags/[2]/string", Value: []byte("tag3")},
+			{KeyURI: "objectID/tags/[3]/string", Value: []byte("tag4")},
+			{KeyURI: "objectID/tags/[4]/string", Value: []byte("tag5")},
+			{KeyURI: "objectID/tags/[5]/string", Value: []byte("tag6")},
+			{KeyURI: "objectID/nested/tags/[0]/string", Value: []byte("tag7")},
+			{KeyURI: "objectID/nested/tags/[1]/string", Value: []byte("tag8")},
+			{KeyURI: "objectID/nested/tags/[2]/string", Value: []byte("tag9")},
+			{KeyURI: "objectID/nested/tags/[3]/string", Value: []byte("tag10")},
+			{KeyURI: "objectID/nested/tags/[4]/string", Value: []byte("tag11")},
+			{KeyURI: "objectID/nested/tags/[5]/string", Value: []byte("tag12")},
 			{KeyURI: "objectID/nested/name/string", Value: []byte("tagger")},
+			{KeyURI: "objectID/tags/array", Value: []byte("6")},
+			{KeyURI: "objectID/nested/tags/array", Value: []byte("6")},
 		},
 	},
 	"Transforms simple object array #2": {
@@ -106,12 +184,13 @@ var testCases = map[string]struct {
 				]
 			}`),
 		propertyList: PropertyEntryList{
-			{KeyURI: "objectID/people/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/people/[0.3]/name/string", Value: []byte("Monroe Roth")},
-			{KeyURI: "objectID/people/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/people/[1.3]/name/string", Value: []byte("Mullen Rhodes")},
-			{KeyURI: "objectID/people/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/people/[2.3]/name/string", Value: []byte("Mcclure Welch")},
+			{KeyURI: "objectID/people/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/people/[0]/name/string", Value: []byte("Monroe Roth")},
+			{KeyURI: "objectID/people/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/people/[1]/name/string", Value: []byte("Mullen Rhodes")},
+			{KeyURI: "objectID/people/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/people/[2]/name/string", Value: []byte("Mcclure Welch")},
+			{KeyURI: "objectID/people/array", Value: []byte("3")},
 		},
 	},
 	"Transforms simple object array #3": {
@@ -120,12 +199,13 @@ var testCases = map[string]struct {
 				"tags": ["tag1","tag2","tag3","tag4","tag5","tag6"]
 			}`),
 		propertyList: PropertyEntryList{
-			{KeyURI: "objectID/tags/[0.6]/string", Value: []byte("tag1")},
-			{KeyURI: "objectID/tags/[1.6]/string", Value: []byte("tag2")},
-			{KeyURI: "objectID/tags/[2.6]/string", Value: []byte("tag3")},
-			{KeyURI: "objectID/tags/[3.6]/string", Value: []byte("tag4")},
-			{KeyURI: "objectID/tags/[4.6]/string", Value: []byte("tag5")},
-			{KeyURI: "objectID/tags/[5.6]/string", Value: []byte("tag6")},
+			{KeyURI: "objectID/tags/[0]/string", Value: []byte("tag1")},
+			{KeyURI: "objectID/tags/[1]/string", Value: []byte("tag2")},
+			{KeyURI: "objectID/tags/[2]/string", Value: []byte("tag3")},
+			{KeyURI: "objectID/tags/[3]/string", Value: []byte("tag4")},
+			{KeyURI: "objectID/tags/[4]/string", Value: []byte("tag5")},
+			{KeyURI: "objectID/tags/[5]/string", Value: []byte("tag6")},
+			{KeyURI: "objectID/tags/array", Value: []byte("6")},
 		},
 	},
 	"Transforms simple object array #4": {
@@ -136,17 +216,21 @@ var testCases = map[string]struct {
 				{ "name":"Fiat", "models":[ "500", "Panda" ] }
 			]`),
 		propertyList: PropertyEntryList{
-			{KeyURI: "objectID/[0.3]/name/string", Value: []byte("Ford")},
-			{KeyURI: "objectID/[0.3]/models/[0.3]/string", Value: []byte("Fiesta")},
-			{KeyURI: "objectID/[0.3]/models/[1.3]/string", Value: []byte("Focus")},
-			{KeyURI: "objectID/[0.3]/models/[2.3]/string", Value: []byte("Mustang")},
-			{KeyURI: "objectID/[1.3]/name/string", Value: []byte("BMW")},
-			{KeyURI: "objectID/[1.3]/models/[0.3]/string", Value: []byte("320")},
-			{KeyURI: "objectID/[1.3]/models/[1.3]/string", Value: []byte("X3")},
-			{KeyURI: "objectID/[1.3]/models/[2.3]/string", Value: []byte("X5")},
-			{KeyURI: "objectID/[2.3]/name/string", Value: []byte("Fiat")},
-			{KeyURI: "objectID/[2.3]/models/[0.2]/string", Value: []byte("500")},
-			{KeyURI: "objectID/[2.3]/models/[1.2]/string", Value: []byte("Panda")},
+			{KeyURI: "objectID/[0]/name/string", Value: []byte("Ford")},
+			{KeyURI: "objectID/[0]/models/[0]/string", Value: []byte("Fiesta")},
+			{KeyURI: "objectID/[0]/models/[1]/string", Value: []byte("Focus")},
+			{KeyURI: "objectID/[0]/models/[2]/string", Value: []byte("Mustang")},
+			{KeyURI: "objectID/[1]/name/string", Value: []byte("BMW")},
+			{KeyURI: "objectID/[1]/models/[0]/string", Value: []byte("320")},
+			{KeyURI: "objectID/[1]/models/[1]/string", Value: []byte("X3")},
+			{KeyURI: "objectID/[1]/models/[2]/string", Value: []byte("X5")},
+			{KeyURI: "objectID/[2]/name/string", Value: []byte("Fiat")},
+			{KeyURI: "objectID/[2]/models/[0]/string", Value: []byte("500")},
+			{KeyURI: "objectID/[2]/models/[1]/string", Value: []byte("Panda")},
+			{KeyURI: "objectID/array", Value: []byte("3")},
+			{KeyURI: "objectID/[0]/models/array", Value: []byte("3")},
+			{KeyURI: "objectID/[1]/models/array", Value: []byte("3")},
+			{KeyURI: "objectID/[2]/models/array", Value: []byte("2")},
 		},
 	},
 	"Transforms complex object #1": {
@@ -182,28 +266,30 @@ var testCases = map[string]struct {
 			{KeyURI: "objectID/name/string", Value: []byte("Cake")},
 			{KeyURI: "objectID/ppu/float64", Value: Float64ToBinary(0.55)},
 			{KeyURI: "objectID/price/nil", Value: nil},
-			{KeyURI: "objectID/batters/batter/[0.4]/id/string", Value: []byte("1001")},
-			{KeyURI: "objectID/batters/batter/[0.4]/type/string", Value: []byte("Regular")},
-			{KeyURI: "objectID/batters/batter/[1.4]/id/string", Value: []byte("1002")},
-			{KeyURI: "objectID/batters/batter/[1.4]/type/string", Value: []byte("Chocolate")},
-			{KeyURI: "objectID/batters/batter/[2.4]/id/string", Value: []byte("1003")},
-			{KeyURI: "objectID/batters/batter/[2.4]/type/string", Value: []byte("Blueberry")},
-			{KeyURI: "objectID/batters/batter/[3.4]/id/string", Value: []byte("1004")},
-			{KeyURI: "objectID/batters/batter/[3.4]/type/string", Value: []byte("Devil's Food")},
-			{KeyURI: "objectID/topping/[0.7]/type/string", Value: []byte("None")},
-			{KeyURI: "objectID/topping/[0.7]/id/string", Value: []byte("5001")},
-			{KeyURI: "objectID/topping/[1.7]/id/string", Value: []byte("5002")},
-			{KeyURI: "objectID/topping/[1.7]/type/string", Value: []byte("Glazed")},
-			{KeyURI: "objectID/topping/[2.7]/id/string", Value: []byte("5005")},
-			{KeyURI: "objectID/topping/[2.7]/type/string", Value: []byte("Sugar")},
-			{KeyURI: "objectID/topping/[3.7]/id/string", Value: []byte("5007")},
-			{KeyURI: "objectID/topping/[3.7]/type/string", Value: []byte("Powdered Sugar")},
-			{KeyURI: "objectID/topping/[4.7]/id/string", Value: []byte("5006")},
-			{KeyURI: "objectID/topping/[4.7]/type/string", Value: []byte("Chocolate with Sprinkles")},
-			{KeyURI: "objectID/topping/[5.7]/id/string", Value: []byte("5003")},
-			{KeyURI: "objectID/topping/[5.7]/type/string", Value: []byte("Chocolate")},
-			{KeyURI: "objectID/topping/[6.7]/id/string", Value: []byte("5004")},
-			{KeyURI: "objectID/topping/[6.7]/type/string", Value: []byte("Maple")},
+			{KeyURI: "objectID/batters/batter/[0]/id/string", Value: []byte("1001")},
+			{KeyURI: "objectID/batters/batter/[0]/type/string", Value: []byte("Regular")},
+			{KeyURI: "objectID/batters/batter/[1]/id/string", Value: []byte("1002")},
+			{KeyURI: "objectID/batters/batter/[1]/type/string", Value: []byte("Chocolate")},
+			{KeyURI: "objectID/batters/batter/[2]/id/string", Value: []byte("1003")},
+			{KeyURI: "objectID/batters/batter/[2]/type/string", Value: []byte("Blueberry")},
+			{KeyURI: "objectID/batters/batter/[3]/id/string", Value: []byte("1004")},
+			{KeyURI: "objectID/batters/batter/[3]/type/string", Value: []byte("Devil's Food")},
+			{KeyURI: "objectID/topping/[0]/type/string", Value: []byte("None")},
+			{KeyURI: "objectID/topping/[0]/id/string", Value: []byte("5001")},
+			{KeyURI: "objectID/topping/[1]/id/string", Value: []byte("5002")},
+			{KeyURI: "objectID/topping/[1]/type/string", Value: []byte("Glazed")},
+			{KeyURI: "objectID/topping/[2]/id/string", Value: []byte("5005")},
+			{KeyURI: "objectID/topping/[2]/type/string", Value: []byte("Sugar")},
+			{KeyURI: "objectID/topping/[3]/id/string", Value: []byte("5007")},
+			{KeyURI: "objectID/topping/[3]/type/string", Value: []byte("Powdered Sugar")},
+			{KeyURI: "objectID/topping/[4]/id/string", Value: []byte("5006")},
+			{KeyURI: "objectID/topping/[4]/type/string", Value: []byte("Chocolate with Sprinkles")},
+			{KeyURI: "objectID/topping/[5]/id/string", Value: []byte("5003")},
+			{KeyURI: "objectID/topping/[5]/type/string", Value: []byte("Chocolate")},
+			{KeyURI: "objectID/topping/[6]/id/string", Value: []byte("5004")},
+			{KeyURI: "objectID/topping/[6]/type/string", Value: []byte("Maple")},
+			{KeyURI: "objectID/batters/batter/array", Value: []byte("4")},
+			{KeyURI: "objectID/topping/array", Value: []byte("7")},
 		},
 	},
 	"Transforms complex object #2": {
@@ -430,293 +516,315 @@ var testCases = map[string]struct {
 			  }
 			]`),
 		propertyList: PropertyEntryList{
-			{KeyURI: "objectID/[0.7]/age/float64", Value: Float64ToBinary(29)},
-			{KeyURI: "objectID/[0.7]/email/string", Value: []byte("rosario.camacho@teraprene.co.uk")},
-			{KeyURI: "objectID/[0.7]/phone/string", Value: []byte("+1 (940) 577-3244")},
-			{KeyURI: "objectID/[0.7]/registered/string", Value: []byte("Friday, November 7, 2014 7:18 PM")},
-			{KeyURI: "objectID/[0.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[0.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[0.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[0.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[0.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[0.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[0.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[0.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[0.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[0.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[0.7]/_id/string", Value: []byte("5f9deaa12b81ec174f75e315")},
-			{KeyURI: "objectID/[0.7]/isActive/bool", Value: []byte("true")},
-			{KeyURI: "objectID/[0.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[0.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[0.7]/friends/[0.3]/name/string", Value: []byte("Clay Nash")},
-			{KeyURI: "objectID/[0.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[0.7]/friends/[1.3]/name/string", Value: []byte("Amanda Warner")},
-			{KeyURI: "objectID/[0.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[0.7]/friends/[2.3]/name/string", Value: []byte("Kirsten Whitehead")},
-			{KeyURI: "objectID/[0.7]/index/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[0.7]/longitude/string", Value: []byte("151.382606")},
-			{KeyURI: "objectID/[0.7]/address/string", Value: []byte("766 Keap Street, Smock, California, 1121")},
-			{KeyURI: "objectID/[0.7]/latitude/string", Value: []byte("-16.92488")},
-			{KeyURI: "objectID/[0.7]/greeting/string", Value: []byte("Hello, Rosario! You have 7 unread messages.")},
-			{KeyURI: "objectID/[0.7]/favoriteFruit/string", Value: []byte("apple")},
-			{KeyURI: "objectID/[0.7]/eyeColor/string", Value: []byte("blue")},
-			{KeyURI: "objectID/[0.7]/name/last/string", Value: []byte("Camacho")},
-			{KeyURI: "objectID/[0.7]/name/first/string", Value: []byte("Rosario")},
-			{KeyURI: "objectID/[0.7]/company/string", Value: []byte("TERAPRENE")},
-			{KeyURI: "objectID/[0.7]/tags/[0.5]/string", Value: []byte("non")},
-			{KeyURI: "objectID/[0.7]/tags/[1.5]/string", Value: []byte("anim")},
-			{KeyURI: "objectID/[0.7]/tags/[2.5]/string", Value: []byte("esse")},
-			{KeyURI: "objectID/[0.7]/tags/[3.5]/string", Value: []byte("nostrud")},
-			{KeyURI: "objectID/[0.7]/tags/[4.5]/string", Value: []byte("veniam")},
-			{KeyURI: "objectID/[0.7]/guid/string", Value: []byte("5e3ba5a0-7d5f-4b70-bf41-7c882b4da1ef")},
-			{KeyURI: "objectID/[0.7]/balance/string", Value: []byte("$3,166.17")},
-			{KeyURI: "objectID/[0.7]/about/string", Value: []byte("Pariatur sint do pariatur dolor eiusmod reprehenderit non ex minim ullamco quis consequat.")},
-			{KeyURI: "objectID/[1.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[1.7]/eyeColor/string", Value: []byte("green")},
-			{KeyURI: "objectID/[1.7]/registered/string", Value: []byte("Wednesday, April 1, 2015 7:06 AM")},
-			{KeyURI: "objectID/[1.7]/_id/string", Value: []byte("5f9deaa1feed31fcbcc6e53e")},
-			{KeyURI: "objectID/[1.7]/isActive/bool", Value: []byte("true")},
-			{KeyURI: "objectID/[1.7]/age/float64", Value: Float64ToBinary(31)},
-			{KeyURI: "objectID/[1.7]/email/string", Value: []byte("randall.conley@besto.me")},
-			{KeyURI: "objectID/[1.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[1.7]/friends/[0.3]/name/string", Value: []byte("Mcfarland Pickett")},
-			{KeyURI: "objectID/[1.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[1.7]/friends/[1.3]/name/string", Value: []byte("Briana Avery")},
-			{KeyURI: "objectID/[1.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[1.7]/friends/[2.3]/name/string", Value: []byte("Noel Hobbs")},
-			{KeyURI: "objectID/[1.7]/guid/string", Value: []byte("b9de6d8c-365e-4ca4-8ddb-218e1ce6f112")},
-			{KeyURI: "objectID/[1.7]/balance/string", Value: []byte("$1,739.73")},
-			{KeyURI: "objectID/[1.7]/company/string", Value: []byte("BESTO")},
-			{KeyURI: "objectID/[1.7]/phone/string", Value: []byte("+1 (891) 595-2961")},
-			{KeyURI: "objectID/[1.7]/about/string", Value: []byte("Exercitation cillum sint dolore aute ex anim deserunt veniam excepteur.")},
-			{KeyURI: "objectID/[1.7]/longitude/string", Value: []byte("81.917956")},
-			{KeyURI: "objectID/[1.7]/tags/[0.5]/string", Value: []byte("ut")},
-			{KeyURI: "objectID/[1.7]/tags/[1.5]/string", Value: []byte("exercitation")},
-			{KeyURI: "objectID/[1.7]/tags/[2.5]/string", Value: []byte("nostrud")},
-			{KeyURI: "objectID/[1.7]/tags/[3.5]/string", Value: []byte("consequat")},
-			{KeyURI: "objectID/[1.7]/tags/[4.5]/string", Value: []byte("esse")},
-			{KeyURI: "objectID/[1.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[1.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[1.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[1.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[1.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[1.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[1.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[1.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[1.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[1.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[1.7]/index/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[1.7]/greeting/string", Value: []byte("Hello, Randall! You have 8 unread messages.")},
-			{KeyURI: "objectID/[1.7]/address/string", Value: []byte("682 Meserole Avenue, Sexton, Georgia, 7532")},
-			{KeyURI: "objectID/[1.7]/latitude/string", Value: []byte("-61.200296")},
-			{KeyURI: "objectID/[1.7]/favoriteFruit/string", Value: []byte("apple")},
-			{KeyURI: "objectID/[1.7]/name/last/string", Value: []byte("Conley")},
-			{KeyURI: "objectID/[1.7]/name/first/string", Value: []byte("Randall")},
-			{KeyURI: "objectID/[2.7]/balance/string", Value: []byte("$2,843.35")},
-			{KeyURI: "objectID/[2.7]/address/string", Value: []byte("716 Sackman Street, Riner, Rhode Island, 4681")},
-			{KeyURI: "objectID/[2.7]/longitude/string", Value: []byte("-39.854437")},
-			{KeyURI: "objectID/[2.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[2.7]/friends/[0.3]/name/string", Value: []byte("Jewel Page")},
-			{KeyURI: "objectID/[2.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[2.7]/friends/[1.3]/name/string", Value: []byte("Merle Fernandez")},
-			{KeyURI: "objectID/[2.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[2.7]/friends/[2.3]/name/string", Value: []byte("Maynard Cohen")},
-			{KeyURI: "objectID/[2.7]/index/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[2.7]/guid/string", Value: []byte("f3c1a57c-622c-413f-a55b-fea9c741feb7")},
-			{KeyURI: "objectID/[2.7]/age/float64", Value: Float64ToBinary(21)},
-			{KeyURI: "objectID/[2.7]/eyeColor/string", Value: []byte("brown")},
-			{KeyURI: "objectID/[2.7]/latitude/string", Value: []byte("-42.93735")},
-			{KeyURI: "objectID/[2.7]/favoriteFruit/string", Value: []byte("apple")},
-			{KeyURI: "objectID/[2.7]/_id/string", Value: []byte("5f9deaa1174da743ea0f9cab")},
-			{KeyURI: "objectID/[2.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[2.7]/name/first/string", Value: []byte("Pam")},
-			{KeyURI: "objectID/[2.7]/name/last/string", Value: []byte("Stein")},
-			{KeyURI: "objectID/[2.7]/company/string", Value: []byte("QUILTIGEN")},
-			{KeyURI: "objectID/[2.7]/about/string", Value: []byte("Nisi incididunt deserunt irure non excepteur sint amet tempor irure Lorem veniam cillum et in.")},
-			{KeyURI: "objectID/[2.7]/tags/[0.5]/string", Value: []byte("tempor")},
-			{KeyURI: "objectID/[2.7]/tags/[1.5]/string", Value: []byte("eiusmod")},
-			{KeyURI: "objectID/[2.7]/tags/[2.5]/string", Value: []byte("pariatur")},
-			{KeyURI: "objectID/[2.7]/tags/[3.5]/string", Value: []byte("mollit")},
-			{KeyURI: "objectID/[2.7]/tags/[4.5]/string", Value: []byte("Lorem")},
-			{KeyURI: "objectID/[2.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[2.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[2.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[2.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[2.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[2.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[2.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[2.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[2.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[2.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[2.7]/isActive/bool", Value: []byte("false")},
-			{KeyURI: "objectID/[2.7]/phone/string", Value: []byte("+1 (854) 493-3172")},
-			{KeyURI: "objectID/[2.7]/registered/string", Value: []byte("Monday, June 30, 2014 3:51 AM")},
-			{KeyURI: "objectID/[2.7]/greeting/string", Value: []byte("Hello, Pam! You have 9 unread messages.")},
-			{KeyURI: "objectID/[2.7]/email/string", Value: []byte("pam.stein@quiltigen.biz")},
-			{KeyURI: "objectID/[3.7]/email/string", Value: []byte("margaret.lamb@viagrand.name")},
-			{KeyURI: "objectID/[3.7]/about/string", Value: []byte("Pariatur nisi minim nostrud irure veniam reprehenderit excepteur eu duis.")},
-			{KeyURI: "objectID/[3.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[3.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[3.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[3.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[3.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[3.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[3.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[3.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[3.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[3.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[3.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[3.7]/age/float64", Value: Float64ToBinary(31)},
-			{KeyURI: "objectID/[3.7]/company/string", Value: []byte("VIAGRAND")},
-			{KeyURI: "objectID/[3.7]/guid/string", Value: []byte("04e86b5b-76a8-4841-953a-1dd617ff0f6d")},
-			{KeyURI: "objectID/[3.7]/balance/string", Value: []byte("$2,774.83")},
-			{KeyURI: "objectID/[3.7]/name/first/string", Value: []byte("Margaret")},
-			{KeyURI: "objectID/[3.7]/name/last/string", Value: []byte("Lamb")},
-			{KeyURI: "objectID/[3.7]/eyeColor/string", Value: []byte("brown")},
-			{KeyURI: "objectID/[3.7]/phone/string", Value: []byte("+1 (830) 474-2690")},
-			{KeyURI: "objectID/[3.7]/address/string", Value: []byte("748 Vandalia Avenue, Brule, Nevada, 1252")},
-			{KeyURI: "objectID/[3.7]/greeting/string", Value: []byte("Hello, Margaret! You have 10 unread messages.")},
-			{KeyURI: "objectID/[3.7]/favoriteFruit/string", Value: []byte("banana")},
-			{KeyURI: "objectID/[3.7]/_id/string", Value: []byte("5f9deaa11b81c7fcdd208a25")},
-			{KeyURI: "objectID/[3.7]/index/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[3.7]/isActive/bool", Value: []byte("true")},
-			{KeyURI: "objectID/[3.7]/tags/[0.5]/string", Value: []byte("anim")},
-			{KeyURI: "objectID/[3.7]/tags/[1.5]/string", Value: []byte("velit")},
-			{KeyURI: "objectID/[3.7]/tags/[2.5]/string", Value: []byte("irure")},
-			{KeyURI: "objectID/[3.7]/tags/[3.5]/string", Value: []byte("adipisicing")},
-			{KeyURI: "objectID/[3.7]/tags/[4.5]/string", Value: []byte("nulla")},
-			{KeyURI: "objectID/[3.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[3.7]/friends/[0.3]/name/string", Value: []byte("Monroe Roth")},
-			{KeyURI: "objectID/[3.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[3.7]/friends/[1.3]/name/string", Value: []byte("Mullen Rhodes")},
-			{KeyURI: "objectID/[3.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[3.7]/friends/[2.3]/name/string", Value: []byte("Mcclure Welch")},
-			{KeyURI: "objectID/[3.7]/registered/string", Value: []byte("Friday, July 10, 2020 6:12 AM")},
-			{KeyURI: "objectID/[3.7]/latitude/string", Value: []byte("-41.341565")},
-			{KeyURI: "objectID/[3.7]/longitude/string", Value: []byte("159.003298")},
-			{KeyURI: "objectID/[4.7]/about/string", Value: []byte("Consequat incididunt aliqua laboris qui. Ex minim voluptate et nostrud.")},
-			{KeyURI: "objectID/[4.7]/favoriteFruit/string", Value: []byte("strawberry")},
-			{KeyURI: "objectID/[4.7]/index/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[4.7]/guid/string", Value: []byte("3698670f-afb5-441a-abdd-bf3b8fb805ff")},
-			{KeyURI: "objectID/[4.7]/name/first/string", Value: []byte("Sherman")},
-			{KeyURI: "objectID/[4.7]/name/last/string", Value: []byte("Stone")},
-			{KeyURI: "objectID/[4.7]/company/string", Value: []byte("GEEKOSIS")},
-			{KeyURI: "objectID/[4.7]/longitude/string", Value: []byte("-65.600476")},
-			{KeyURI: "objectID/[4.7]/greeting/string", Value: []byte("Hello, Sherman! You have 5 unread messages.")},
-			{KeyURI: "objectID/[4.7]/isActive/bool", Value: []byte("true")},
-			{KeyURI: "objectID/[4.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[4.7]/eyeColor/string", Value: []byte("green")},
-			{KeyURI: "objectID/[4.7]/email/string", Value: []byte("sherman.stone@geekosis.org")},
-			{KeyURI: "objectID/[4.7]/latitude/string", Value: []byte("9.795218")},
-			{KeyURI: "objectID/[4.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[4.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[4.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[4.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[4.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[4.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[4.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[4.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[4.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[4.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[4.7]/_id/string", Value: []byte("5f9deaa1551e387447129d68")},
-			{KeyURI: "objectID/[4.7]/age/float64", Value: Float64ToBinary(34)},
-			{KeyURI: "objectID/[4.7]/address/string", Value: []byte("259 Rapelye Street, Sandston, Connecticut, 8045")},
-			{KeyURI: "objectID/[4.7]/registered/string", Value: []byte("Friday, October 31, 2014 4:57 PM")},
-			{KeyURI: "objectID/[4.7]/tags/[0.5]/string", Value: []byte("cillum")},
-			{KeyURI: "objectID/[4.7]/tags/[1.5]/string", Value: []byte("labore")},
-			{KeyURI: "objectID/[4.7]/tags/[2.5]/string", Value: []byte("incididunt")},
-			{KeyURI: "objectID/[4.7]/tags/[3.5]/string", Value: []byte("sit")},
-			{KeyURI: "objectID/[4.7]/tags/[4.5]/string", Value: []byte("amet")},
-			{KeyURI: "objectID/[4.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[4.7]/friends/[0.3]/name/string", Value: []byte("Whitehead Pugh")},
-			{KeyURI: "objectID/[4.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[4.7]/friends/[1.3]/name/string", Value: []byte("Mable Villarreal")},
-			{KeyURI: "objectID/[4.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[4.7]/friends/[2.3]/name/string", Value: []byte("Lowery Floyd")},
-			{KeyURI: "objectID/[4.7]/balance/string", Value: []byte("$1,036.21")},
-			{KeyURI: "objectID/[4.7]/phone/string", Value: []byte("+1 (903) 461-3017")},
-			{KeyURI: "objectID/[5.7]/phone/string", Value: []byte("+1 (926) 516-2079")},
-			{KeyURI: "objectID/[5.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[5.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[5.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[5.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[5.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[5.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[5.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[5.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[5.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[5.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[5.7]/isActive/bool", Value: []byte("true")},
-			{KeyURI: "objectID/[5.7]/balance/string", Value: []byte("$3,870.62")},
-			{KeyURI: "objectID/[5.7]/email/string", Value: []byte("duran.small@talkola.info")},
-			{KeyURI: "objectID/[5.7]/about/string", Value: []byte("Nostrud aliquip consequat do sint ipsum pariatur ut mollit.")},
-			{KeyURI: "objectID/[5.7]/latitude/string", Value: []byte("16.614664")},
-			{KeyURI: "objectID/[5.7]/longitude/string", Value: []byte("-131.206816")},
-			{KeyURI: "objectID/[5.7]/tags/[0.5]/string", Value: []byte("quis")},
-			{KeyURI: "objectID/[5.7]/tags/[1.5]/string", Value: []byte("duis")},
-			{KeyURI: "objectID/[5.7]/tags/[2.5]/string", Value: []byte("enim")},
-			{KeyURI: "objectID/[5.7]/tags/[3.5]/string", Value: []byte("culpa")},
-			{KeyURI: "objectID/[5.7]/tags/[4.5]/string", Value: []byte("est")},
-			{KeyURI: "objectID/[5.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[5.7]/friends/[0.3]/name/string", Value: []byte("Maritza Gordon")},
-			{KeyURI: "objectID/[5.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[5.7]/friends/[1.3]/name/string", Value: []byte("Araceli Carey")},
-			{KeyURI: "objectID/[5.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[5.7]/friends/[2.3]/name/string", Value: []byte("Stark Payne")},
-			{KeyURI: "objectID/[5.7]/guid/string", Value: []byte("63df9954-ec43-4fda-b7a5-214a88681b3a")},
-			{KeyURI: "objectID/[5.7]/age/float64", Value: Float64ToBinary(21)},
-			{KeyURI: "objectID/[5.7]/address/string", Value: []byte("435 Calyer Street, Sperryville, Oregon, 112")},
-			{KeyURI: "objectID/[5.7]/greeting/string", Value: []byte("Hello, Duran! You have 10 unread messages.")},
-			{KeyURI: "objectID/[5.7]/favoriteFruit/string", Value: []byte("strawberry")},
-			{KeyURI: "objectID/[5.7]/index/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[5.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[5.7]/company/string", Value: []byte("TALKOLA")},
-			{KeyURI: "objectID/[5.7]/registered/string", Value: []byte("Monday, September 29, 2014 1:24 PM")},
-			{KeyURI: "objectID/[5.7]/_id/string", Value: []byte("5f9deaa13006ef7b913a5cc0")},
-			{KeyURI: "objectID/[5.7]/eyeColor/string", Value: []byte("brown")},
-			{KeyURI: "objectID/[5.7]/name/first/string", Value: []byte("Duran")},
-			{KeyURI: "objectID/[5.7]/name/last/string", Value: []byte("Small")},
-			{KeyURI: "objectID/[6.7]/index/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[6.7]/email/string", Value: []byte("alana.hart@skyplex.ca")},
-			{KeyURI: "objectID/[6.7]/registered/string", Value: []byte("Sunday, April 10, 2016 11:35 AM")},
-			{KeyURI: "objectID/[6.7]/favoriteFruit/string", Value: []byte("apple")},
-			{KeyURI: "objectID/[6.7]/range/[0.10]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[6.7]/range/[1.10]/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[6.7]/range/[2.10]/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[6.7]/range/[3.10]/float64", Value: Float64ToBinary(3)},
-			{KeyURI: "objectID/[6.7]/range/[4.10]/float64", Value: Float64ToBinary(4)},
-			{KeyURI: "objectID/[6.7]/range/[5.10]/float64", Value: Float64ToBinary(5)},
-			{KeyURI: "objectID/[6.7]/range/[6.10]/float64", Value: Float64ToBinary(6)},
-			{KeyURI: "objectID/[6.7]/range/[7.10]/float64", Value: Float64ToBinary(7)},
-			{KeyURI: "objectID/[6.7]/range/[8.10]/float64", Value: Float64ToBinary(8)},
-			{KeyURI: "objectID/[6.7]/range/[9.10]/float64", Value: Float64ToBinary(9)},
-			{KeyURI: "objectID/[6.7]/_id/string", Value: []byte("5f9deaa15a54a8ab95b82e76")},
-			{KeyURI: "objectID/[6.7]/balance/string", Value: []byte("$1,692.48")},
-			{KeyURI: "objectID/[6.7]/company/string", Value: []byte("SKYPLEX")},
-			{KeyURI: "objectID/[6.7]/phone/string", Value: []byte("+1 (824) 504-3286")},
-			{KeyURI: "objectID/[6.7]/about/string", Value: []byte("Ea dolore enim proident sint do commodo irure reprehenderit fugiat.")},
-			{KeyURI: "objectID/[6.7]/longitude/string", Value: []byte("68.660711")},
-			{KeyURI: "objectID/[6.7]/tags/[0.5]/string", Value: []byte("nostrud")},
-			{KeyURI: "objectID/[6.7]/tags/[1.5]/string", Value: []byte("fugiat")},
-			{KeyURI: "objectID/[6.7]/tags/[2.5]/string", Value: []byte("aute")},
-			{KeyURI: "objectID/[6.7]/tags/[3.5]/string", Value: []byte("labore")},
-			{KeyURI: "objectID/[6.7]/tags/[4.5]/string", Value: []byte("et")},
-			{KeyURI: "objectID/[6.7]/guid/string", Value: []byte("c34629ad-de7a-4f88-b62d-99505ab9bcc5")},
-			{KeyURI: "objectID/[6.7]/isActive/bool", Value: []byte("false")},
-			{KeyURI: "objectID/[6.7]/eyeColor/string", Value: []byte("brown")},
-			{KeyURI: "objectID/[6.7]/name/first/string", Value: []byte("Alana")},
-			{KeyURI: "objectID/[6.7]/name/last/string", Value: []byte("Hart")},
-			{KeyURI: "objectID/[6.7]/address/string", Value: []byte("285 Veranda Place, Kula, Wisconsin, 3318")},
-			{KeyURI: "objectID/[6.7]/picture/string", Value: []byte("http://placehold.it/32x32")},
-			{KeyURI: "objectID/[6.7]/age/float64", Value: Float64ToBinary(30)},
-			{KeyURI: "objectID/[6.7]/latitude/string", Value: []byte("42.68548")},
-			{KeyURI: "objectID/[6.7]/friends/[0.3]/id/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/[6.7]/friends/[0.3]/name/string", Value: []byte("Atkinson Thomas")},
-			{KeyURI: "objectID/[6.7]/friends/[1.3]/id/float64", Value: Float64ToBinary(1)},
-			{KeyURI: "objectID/[6.7]/friends/[1.3]/name/string", Value: []byte("Iva Curtis")},
-			{KeyURI: "objectID/[6.7]/friends/[2.3]/id/float64", Value: Float64ToBinary(2)},
-			{KeyURI: "objectID/[6.7]/friends/[2.3]/name/string", Value: []byte("King Burnett")},
-			{KeyURI: "objectID/[6.7]/greeting/string", Value: []byte("Hello, Alana! You have 8 unread messages.")},
+			{KeyURI: "objectID/[0]/age/float64", Value: Float64ToBinary(29)},
+			{KeyURI: "objectID/[0]/email/string", Value: []byte("rosario.camacho@teraprene.co.uk")},
+			{KeyURI: "objectID/[0]/phone/string", Value: []byte("+1 (940) 577-3244")},
+			{KeyURI: "objectID/[0]/registered/string", Value: []byte("Friday, November 7, 2014 7:18 PM")},
+			{KeyURI: "objectID/[0]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[0]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[0]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[0]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[0]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[0]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[0]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[0]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[0]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[0]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[0]/_id/string", Value: []byte("5f9deaa12b81ec174f75e315")},
+			{KeyURI: "objectID/[0]/isActive/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[0]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[0]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[0]/friends/[0]/name/string", Value: []byte("Clay Nash")},
+			{KeyURI: "objectID/[0]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[0]/friends/[1]/name/string", Value: []byte("Amanda Warner")},
+			{KeyURI: "objectID/[0]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[0]/friends/[2]/name/string", Value: []byte("Kirsten Whitehead")},
+			{KeyURI: "objectID/[0]/index/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[0]/longitude/string", Value: []byte("151.382606")},
+			{KeyURI: "objectID/[0]/address/string", Value: []byte("766 Keap Street, Smock, California, 1121")},
+			{KeyURI: "objectID/[0]/latitude/string", Value: []byte("-16.92488")},
+			{KeyURI: "objectID/[0]/greeting/string", Value: []byte("Hello, Rosario! You have 7 unread messages.")},
+			{KeyURI: "objectID/[0]/favoriteFruit/string", Value: []byte("apple")},
+			{KeyURI: "objectID/[0]/eyeColor/string", Value: []byte("blue")},
+			{KeyURI: "objectID/[0]/name/last/string", Value: []byte("Camacho")},
+			{KeyURI: "objectID/[0]/name/first/string", Value: []byte("Rosario")},
+			{KeyURI: "objectID/[0]/company/string", Value: []byte("TERAPRENE")},
+			{KeyURI: "objectID/[0]/tags/[0]/string", Value: []byte("non")},
+			{KeyURI: "objectID/[0]/tags/[1]/string", Value: []byte("anim")},
+			{KeyURI: "objectID/[0]/tags/[2]/string", Value: []byte("esse")},
+			{KeyURI: "objectID/[0]/tags/[3]/string", Value: []byte("nostrud")},
+			{KeyURI: "objectID/[0]/tags/[4]/string", Value: []byte("veniam")},
+			{KeyURI: "objectID/[0]/guid/string", Value: []byte("5e3ba5a0-7d5f-4b70-bf41-7c882b4da1ef")},
+			{KeyURI: "objectID/[0]/balance/string", Value: []byte("$3,166.17")},
+			{KeyURI: "objectID/[0]/about/string", Value: []byte("Pariatur sint do pariatur dolor eiusmod reprehenderit non ex minim ullamco quis consequat.")},
+			{KeyURI: "objectID/[1]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[1]/eyeColor/string", Value: []byte("green")},
+			{KeyURI: "objectID/[1]/registered/string", Value: []byte("Wednesday, April 1, 2015 7:06 AM")},
+			{KeyURI: "objectID/[1]/_id/string", Value: []byte("5f9deaa1feed31fcbcc6e53e")},
+			{KeyURI: "objectID/[1]/isActive/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[1]/age/float64", Value: Float64ToBinary(31)},
+			{KeyURI: "objectID/[1]/email/string", Value: []byte("randall.conley@besto.me")},
+			{KeyURI: "objectID/[1]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[1]/friends/[0]/name/string", Value: []byte("Mcfarland Pickett")},
+			{KeyURI: "objectID/[1]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[1]/friends/[1]/name/string", Value: []byte("Briana Avery")},
+			{KeyURI: "objectID/[1]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[1]/friends/[2]/name/string", Value: []byte("Noel Hobbs")},
+			{KeyURI: "objectID/[1]/guid/string", Value: []byte("b9de6d8c-365e-4ca4-8ddb-218e1ce6f112")},
+			{KeyURI: "objectID/[1]/balance/string", Value: []byte("$1,739.73")},
+			{KeyURI: "objectID/[1]/company/string", Value: []byte("BESTO")},
+			{KeyURI: "objectID/[1]/phone/string", Value: []byte("+1 (891) 595-2961")},
+			{KeyURI: "objectID/[1]/about/string", Value: []byte("Exercitation cillum sint dolore aute ex anim deserunt veniam excepteur.")},
+			{KeyURI: "objectID/[1]/longitude/string", Value: []byte("81.917956")},
+			{KeyURI: "objectID/[1]/tags/[0]/string", Value: []byte("ut")},
+			{KeyURI: "objectID/[1]/tags/[1]/string", Value: []byte("exercitation")},
+			{KeyURI: "objectID/[1]/tags/[2]/string", Value: []byte("nostrud")},
+			{KeyURI: "objectID/[1]/tags/[3]/string", Value: []byte("consequat")},
+			{KeyURI: "objectID/[1]/tags/[4]/string", Value: []byte("esse")},
+			{KeyURI: "objectID/[1]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[1]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[1]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[1]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[1]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[1]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[1]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[1]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[1]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[1]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[1]/index/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[1]/greeting/string", Value: []byte("Hello, Randall! You have 8 unread messages.")},
+			{KeyURI: "objectID/[1]/address/string", Value: []byte("682 Meserole Avenue, Sexton, Georgia, 7532")},
+			{KeyURI: "objectID/[1]/latitude/string", Value: []byte("-61.200296")},
+			{KeyURI: "objectID/[1]/favoriteFruit/string", Value: []byte("apple")},
+			{KeyURI: "objectID/[1]/name/last/string", Value: []byte("Conley")},
+			{KeyURI: "objectID/[1]/name/first/string", Value: []byte("Randall")},
+			{KeyURI: "objectID/[2]/balance/string", Value: []byte("$2,843.35")},
+			{KeyURI: "objectID/[2]/address/string", Value: []byte("716 Sackman Street, Riner, Rhode Island, 4681")},
+			{KeyURI: "objectID/[2]/longitude/string", Value: []byte("-39.854437")},
+			{KeyURI: "objectID/[2]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[2]/friends/[0]/name/string", Value: []byte("Jewel Page")},
+			{KeyURI: "objectID/[2]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[2]/friends/[1]/name/string", Value: []byte("Merle Fernandez")},
+			{KeyURI: "objectID/[2]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[2]/friends/[2]/name/string", Value: []byte("Maynard Cohen")},
+			{KeyURI: "objectID/[2]/index/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[2]/guid/string", Value: []byte("f3c1a57c-622c-413f-a55b-fea9c741feb7")},
+			{KeyURI: "objectID/[2]/age/float64", Value: Float64ToBinary(21)},
+			{KeyURI: "objectID/[2]/eyeColor/string", Value: []byte("brown")},
+			{KeyURI: "objectID/[2]/latitude/string", Value: []byte("-42.93735")},
+			{KeyURI: "objectID/[2]/favoriteFruit/string", Value: []byte("apple")},
+			{KeyURI: "objectID/[2]/_id/string", Value: []byte("5f9deaa1174da743ea0f9cab")},
+			{KeyURI: "objectID/[2]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[2]/name/first/string", Value: []byte("Pam")},
+			{KeyURI: "objectID/[2]/name/last/string", Value: []byte("Stein")},
+			{KeyURI: "objectID/[2]/company/string", Value: []byte("QUILTIGEN")},
+			{KeyURI: "objectID/[2]/about/string", Value: []byte("Nisi incididunt deserunt irure non excepteur sint amet tempor irure Lorem veniam cillum et in.")},
+			{KeyURI: "objectID/[2]/tags/[0]/string", Value: []byte("tempor")},
+			{KeyURI: "objectID/[2]/tags/[1]/string", Value: []byte("eiusmod")},
+			{KeyURI: "objectID/[2]/tags/[2]/string", Value: []byte("pariatur")},
+			{KeyURI: "objectID/[2]/tags/[3]/string", Value: []byte("mollit")},
+			{KeyURI: "objectID/[2]/tags/[4]/string", Value: []byte("Lorem")},
+			{KeyURI: "objectID/[2]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[2]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[2]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[2]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[2]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[2]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[2]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[2]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[2]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[2]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[2]/isActive/bool", Value: []byte("false")},
+			{KeyURI: "objectID/[2]/phone/string", Value: []byte("+1 (854) 493-3172")},
+			{KeyURI: "objectID/[2]/registered/string", Value: []byte("Monday, June 30, 2014 3:51 AM")},
+			{KeyURI: "objectID/[2]/greeting/string", Value: []byte("Hello, Pam! You have 9 unread messages.")},
+			{KeyURI: "objectID/[2]/email/string", Value: []byte("pam.stein@quiltigen.biz")},
+			{KeyURI: "objectID/[3]/email/string", Value: []byte("margaret.lamb@viagrand.name")},
+			{KeyURI: "objectID/[3]/about/string", Value: []byte("Pariatur nisi minim nostrud irure veniam reprehenderit excepteur eu duis.")},
+			{KeyURI: "objectID/[3]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[3]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[3]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[3]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[3]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[3]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[3]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[3]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[3]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[3]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[3]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[3]/age/float64", Value: Float64ToBinary(31)},
+			{KeyURI: "objectID/[3]/company/string", Value: []byte("VIAGRAND")},
+			{KeyURI: "objectID/[3]/guid/string", Value: []byte("04e86b5b-76a8-4841-953a-1dd617ff0f6d")},
+			{KeyURI: "objectID/[3]/balance/string", Value: []byte("$2,774.83")},
+			{KeyURI: "objectID/[3]/name/first/string", Value: []byte("Margaret")},
+			{KeyURI: "objectID/[3]/name/last/string", Value: []byte("Lamb")},
+			{KeyURI: "objectID/[3]/eyeColor/string", Value: []byte("brown")},
+			{KeyURI: "objectID/[3]/phone/string", Value: []byte("+1 (830) 474-2690")},
+			{KeyURI: "objectID/[3]/address/string", Value: []byte("748 Vandalia Avenue, Brule, Nevada, 1252")},
+			{KeyURI: "objectID/[3]/greeting/string", Value: []byte("Hello, Margaret! You have 10 unread messages.")},
+			{KeyURI: "objectID/[3]/favoriteFruit/string", Value: []byte("banana")},
+			{KeyURI: "objectID/[3]/_id/string", Value: []byte("5f9deaa11b81c7fcdd208a25")},
+			{KeyURI: "objectID/[3]/index/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[3]/isActive/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[3]/tags/[0]/string", Value: []byte("anim")},
+			{KeyURI: "objectID/[3]/tags/[1]/string", Value: []byte("velit")},
+			{KeyURI: "objectID/[3]/tags/[2]/string", Value: []byte("irure")},
+			{KeyURI: "objectID/[3]/tags/[3]/string", Value: []byte("adipisicing")},
+			{KeyURI: "objectID/[3]/tags/[4]/string", Value: []byte("nulla")},
+			{KeyURI: "objectID/[3]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[3]/friends/[0]/name/string", Value: []byte("Monroe Roth")},
+			{KeyURI: "objectID/[3]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[3]/friends/[1]/name/string", Value: []byte("Mullen Rhodes")},
+			{KeyURI: "objectID/[3]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[3]/friends/[2]/name/string", Value: []byte("Mcclure Welch")},
+			{KeyURI: "objectID/[3]/registered/string", Value: []byte("Friday, July 10, 2020 6:12 AM")},
+			{KeyURI: "objectID/[3]/latitude/string", Value: []byte("-41.341565")},
+			{KeyURI: "objectID/[3]/longitude/string", Value: []byte("159.003298")},
+			{KeyURI: "objectID/[4]/about/string", Value: []byte("Consequat incididunt aliqua laboris qui. Ex minim voluptate et nostrud.")},
+			{KeyURI: "objectID/[4]/favoriteFruit/string", Value: []byte("strawberry")},
+			{KeyURI: "objectID/[4]/index/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[4]/guid/string", Value: []byte("3698670f-afb5-441a-abdd-bf3b8fb805ff")},
+			{KeyURI: "objectID/[4]/name/first/string", Value: []byte("Sherman")},
+			{KeyURI: "objectID/[4]/name/last/string", Value: []byte("Stone")},
+			{KeyURI: "objectID/[4]/company/string", Value: []byte("GEEKOSIS")},
+			{KeyURI: "objectID/[4]/longitude/string", Value: []byte("-65.600476")},
+			{KeyURI: "objectID/[4]/greeting/string", Value: []byte("Hello, Sherman! You have 5 unread messages.")},
+			{KeyURI: "objectID/[4]/isActive/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[4]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[4]/eyeColor/string", Value: []byte("green")},
+			{KeyURI: "objectID/[4]/email/string", Value: []byte("sherman.stone@geekosis.org")},
+			{KeyURI: "objectID/[4]/latitude/string", Value: []byte("9.795218")},
+			{KeyURI: "objectID/[4]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[4]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[4]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[4]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[4]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[4]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[4]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[4]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[4]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[4]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[4]/_id/string", Value: []byte("5f9deaa1551e387447129d68")},
+			{KeyURI: "objectID/[4]/age/float64", Value: Float64ToBinary(34)},
+			{KeyURI: "objectID/[4]/address/string", Value: []byte("259 Rapelye Street, Sandston, Connecticut, 8045")},
+			{KeyURI: "objectID/[4]/registered/string", Value: []byte("Friday, October 31, 2014 4:57 PM")},
+			{KeyURI: "objectID/[4]/tags/[0]/string", Value: []byte("cillum")},
+			{KeyURI: "objectID/[4]/tags/[1]/string", Value: []byte("labore")},
+			{KeyURI: "objectID/[4]/tags/[2]/string", Value: []byte("incididunt")},
+			{KeyURI: "objectID/[4]/tags/[3]/string", Value: []byte("sit")},
+			{KeyURI: "objectID/[4]/tags/[4]/string", Value: []byte("amet")},
+			{KeyURI: "objectID/[4]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[4]/friends/[0]/name/string", Value: []byte("Whitehead Pugh")},
+			{KeyURI: "objectID/[4]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[4]/friends/[1]/name/string", Value: []byte("Mable Villarreal")},
+			{KeyURI: "objectID/[4]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[4]/friends/[2]/name/string", Value: []byte("Lowery Floyd")},
+			{KeyURI: "objectID/[4]/balance/string", Value: []byte("$1,036.21")},
+			{KeyURI: "objectID/[4]/phone/string", Value: []byte("+1 (903) 461-3017")},
+			{KeyURI: "objectID/[5]/phone/string", Value: []byte("+1 (926) 516-2079")},
+			{KeyURI: "objectID/[5]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[5]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[5]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[5]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[5]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[5]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[5]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[5]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[5]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[5]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[5]/isActive/bool", Value: []byte("true")},
+			{KeyURI: "objectID/[5]/balance/string", Value: []byte("$3,870.62")},
+			{KeyURI: "objectID/[5]/email/string", Value: []byte("duran.small@talkola.info")},
+			{KeyURI: "objectID/[5]/about/string", Value: []byte("Nostrud aliquip consequat do sint ipsum pariatur ut mollit.")},
+			{KeyURI: "objectID/[5]/latitude/string", Value: []byte("16.614664")},
+			{KeyURI: "objectID/[5]/longitude/string", Value: []byte("-131.206816")},
+			{KeyURI: "objectID/[5]/tags/[0]/string", Value: []byte("quis")},
+			{KeyURI: "objectID/[5]/tags/[1]/string", Value: []byte("duis")},
+			{KeyURI: "objectID/[5]/tags/[2]/string", Value: []byte("enim")},
+			{KeyURI: "objectID/[5]/tags/[3]/string", Value: []byte("culpa")},
+			{KeyURI: "objectID/[5]/tags/[4]/string", Value: []byte("est")},
+			{KeyURI: "objectID/[5]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[5]/friends/[0]/name/string", Value: []byte("Maritza Gordon")},
+			{KeyURI: "objectID/[5]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[5]/friends/[1]/name/string", Value: []byte("Araceli Carey")},
+			{KeyURI: "objectID/[5]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[5]/friends/[2]/name/string", Value: []byte("Stark Payne")},
+			{KeyURI: "objectID/[5]/guid/string", Value: []byte("63df9954-ec43-4fda-b7a5-214a88681b3a")},
+			{KeyURI: "objectID/[5]/age/float64", Value: Float64ToBinary(21)},
+			{KeyURI: "objectID/[5]/address/string", Value: []byte("435 Calyer Street, Sperryville, Oregon, 112")},
+			{KeyURI: "objectID/[5]/greeting/string", Value: []byte("Hello, Duran! You have 10 unread messages.")},
+			{KeyURI: "objectID/[5]/favoriteFruit/string", Value: []byte("strawberry")},
+			{KeyURI: "objectID/[5]/index/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[5]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[5]/company/string", Value: []byte("TALKOLA")},
+			{KeyURI: "objectID/[5]/registered/string", Value: []byte("Monday, September 29, 2014 1:24 PM")},
+			{KeyURI: "objectID/[5]/_id/string", Value: []byte("5f9deaa13006ef7b913a5cc0")},
+			{KeyURI: "objectID/[5]/eyeColor/string", Value: []byte("brown")},
+			{KeyURI: "objectID/[5]/name/first/string", Value: []byte("Duran")},
+			{KeyURI: "objectID/[5]/name/last/string", Value: []byte("Small")},
+			{KeyURI: "objectID/[6]/index/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[6]/email/string", Value: []byte("alana.hart@skyplex.ca")},
+			{KeyURI: "objectID/[6]/registered/string", Value: []byte("Sunday, April 10, 2016 11:35 AM")},
+			{KeyURI: "objectID/[6]/favoriteFruit/string", Value: []byte("apple")},
+			{KeyURI: "objectID/[6]/range/[0]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[6]/range/[1]/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[6]/range/[2]/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[6]/range/[3]/float64", Value: Float64ToBinary(3)},
+			{KeyURI: "objectID/[6]/range/[4]/float64", Value: Float64ToBinary(4)},
+			{KeyURI: "objectID/[6]/range/[5]/float64", Value: Float64ToBinary(5)},
+			{KeyURI: "objectID/[6]/range/[6]/float64", Value: Float64ToBinary(6)},
+			{KeyURI: "objectID/[6]/range/[7]/float64", Value: Float64ToBinary(7)},
+			{KeyURI: "objectID/[6]/range/[8]/float64", Value: Float64ToBinary(8)},
+			{KeyURI: "objectID/[6]/range/[9]/float64", Value: Float64ToBinary(9)},
+			{KeyURI: "objectID/[6]/_id/string", Value: []byte("5f9deaa15a54a8ab95b82e76")},
+			{KeyURI: "objectID/[6]/balance/string", Value: []byte("$1,692.48")},
+			{KeyURI: "objectID/[6]/company/string", Value: []byte("SKYPLEX")},
+			{KeyURI: "objectID/[6]/phone/string", Value: []byte("+1 (824) 504-3286")},
+			{KeyURI: "objectID/[6]/about/string", Value: []byte("Ea dolore enim proident sint do commodo irure reprehenderit fugiat.")},
+			{KeyURI: "objectID/[6]/longitude/string", Value: []byte("68.660711")},
+			{KeyURI: "objectID/[6]/tags/[0]/string", Value: []byte("nostrud")},
+			{KeyURI: "objectID/[6]/tags/[1]/string", Value: []byte("fugiat")},
+			{KeyURI: "objectID/[6]/tags/[2]/string", Value: []byte("aute")},
+			{KeyURI: "objectID/[6]/tags/[3]/string", Value: []byte("labore")},
+			{KeyURI: "objectID/[6]/tags/[4]/string", Value: []byte("et")},
+			{KeyURI: "objectID/[6]/guid/string", Value: []byte("c34629ad-de7a-4f88-b62d-99505ab9bcc5")},
+			{KeyURI: "objectID/[6]/isActive/bool", Value: []byte("false")},
+			{KeyURI: "objectID/[6]/eyeColor/string", Value: []byte("brown")},
+			{KeyURI: "objectID/[6]/name/first/string", Value: []byte("Alana")},
+			{KeyURI: "objectID/[6]/name/last/string", Value: []byte("Hart")},
+			{KeyURI: "objectID/[6]/address/string", Value: []byte("285 Veranda Place, Kula, Wisconsin, 3318")},
+			{KeyURI: "objectID/[6]/picture/string", Value: []byte("http://placehold.it/32x32")},
+			{KeyURI: "objectID/[6]/age/float64", Value: Float64ToBinary(30)},
+			{KeyURI: "objectID/[6]/latitude/string", Value: []byte("42.68548")},
+			{KeyURI: "objectID/[6]/friends/[0]/id/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/[6]/friends/[0]/name/string", Value: []byte("Atkinson Thomas")},
+			{KeyURI: "objectID/[6]/friends/[1]/id/float64", Value: Float64ToBinary(1)},
+			{KeyURI: "objectID/[6]/friends/[1]/name/string", Value: []byte("Iva Curtis")},
+			{KeyURI: "objectID/[6]/friends/[2]/id/float64", Value: Float64ToBinary(2)},
+			{KeyURI: "objectID/[6]/friends/[2]/name/string", Value: []byte("King Burnett")},
+			{KeyURI: "objectID/[6]/greeting/string", Value: []byte("Hello, Alana! You have 8 unread messages.")},
+			{KeyURI: "objectID/array", Value: []byte("7")},
+			{KeyURI: "objectID/[0]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[0]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[0]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[1]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[1]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[1]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[2]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[2]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[2]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[3]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[3]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[3]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[4]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[4]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[4]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[5]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[5]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[5]/friends/array", Value: []byte("3")},
+			{KeyURI: "objectID/[6]/range/array", Value: []byte("10")},
+			{KeyURI: "objectID/[6]/tags/array", Value: []byte("5")},
+			{KeyURI: "objectID/[6]/friends/array", Value: []byte("3")},
 		},
 	},
 	"Transform object with chained arrays": {
@@ -778,55 +886,69 @@ var testCases = map[string]struct {
 		]}`),
 		propertyList: PropertyEntryList{
 			{KeyURI: "objectID/type/string", Value: []byte("FeatureCollection")},
-			{KeyURI: "objectID/features/[0.2]/type/string", Value: []byte("Feature")},
-			{KeyURI: "objectID/features/[0.2]/properties/MAPBLKLOT/string", Value: []byte("0001001")},
-			{KeyURI: "objectID/features/[0.2]/properties/BLKLOT/string", Value: []byte("0001001")},
-			{KeyURI: "objectID/features/[0.2]/properties/FROM_ST/string", Value: []byte("0")},
-			{KeyURI: "objectID/features/[0.2]/properties/STREET/string", Value: []byte("UNKNOWN")},
-			{KeyURI: "objectID/features/[0.2]/properties/ODD_EVEN/string", Value: []byte("E")},
-			{KeyURI: "objectID/features/[0.2]/properties/BLOCK_NUM/string", Value: []byte("0001")},
-			{KeyURI: "objectID/features/[0.2]/properties/LOT_NUM/string", Value: []byte("001")},
-			{KeyURI: "objectID/features/[0.2]/properties/TO_ST/string", Value: []byte("0")},
-			{KeyURI: "objectID/features/[0.2]/properties/ST_TYPE/nil", Value: nil},
-			{KeyURI: "objectID/features/[0.2]/geometry/type/string", Value: []byte("Polygon")},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[0.5]/[0.3]/float64", Value: Float64ToBinary(-122.42200352825247)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[0.5]/[1.3]/float64", Value: Float64ToBinary(37.80848009696725)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[0.5]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[1.5]/[0.3]/float64", Value: Float64ToBinary(-122.42207601332528)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[1.5]/[1.3]/float64", Value: Float64ToBinary(37.808835019815085)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[1.5]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[2.5]/[0.3]/float64", Value: Float64ToBinary(-122.42110217434863)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[2.5]/[1.3]/float64", Value: Float64ToBinary(37.808803534992904)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[2.5]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[3.5]/[0.3]/float64", Value: Float64ToBinary(-122.42106256906727)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[3.5]/[1.3]/float64", Value: Float64ToBinary(37.80860105681815)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[3.5]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[4.5]/[0.3]/float64", Value: Float64ToBinary(-122.42200352825247)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[4.5]/[1.3]/float64", Value: Float64ToBinary(37.80848009696725)},
-			{KeyURI: "objectID/features/[0.2]/geometry/coordinates/[0.1]/[4.5]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[1.2]/type/string", Value: []byte("Feature")},
-			{KeyURI: "objectID/features/[1.2]/properties/STREET/string", Value: []byte("UNKNOWN")},
-			{KeyURI: "objectID/features/[1.2]/properties/ODD_EVEN/string", Value: []byte("E")},
-			{KeyURI: "objectID/features/[1.2]/properties/MAPBLKLOT/string", Value: []byte("0004002")},
-			{KeyURI: "objectID/features/[1.2]/properties/BLOCK_NUM/string", Value: []byte("0004")},
-			{KeyURI: "objectID/features/[1.2]/properties/LOT_NUM/string", Value: []byte("002")},
-			{KeyURI: "objectID/features/[1.2]/properties/FROM_ST/string", Value: []byte("0")},
-			{KeyURI: "objectID/features/[1.2]/properties/BLKLOT/string", Value: []byte("0004002")},
-			{KeyURI: "objectID/features/[1.2]/properties/TO_ST/string", Value: []byte("0")},
-			{KeyURI: "objectID/features/[1.2]/properties/ST_TYPE/nil", Value: nil},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[0.4]/[0.3]/float64", Value: Float64ToBinary(-122.41570120460688)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[0.4]/[1.3]/float64", Value: Float64ToBinary(37.80832725267146)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[0.4]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[1.4]/[0.3]/float64", Value: Float64ToBinary(-122.4157607435932)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[1.4]/[1.3]/float64", Value: Float64ToBinary(37.808630700240904)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[1.4]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[2.4]/[0.3]/float64", Value: Float64ToBinary(-122.4137878913324)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[2.4]/[1.3]/float64", Value: Float64ToBinary(37.80856680131984)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[2.4]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[3.4]/[0.3]/float64", Value: Float64ToBinary(-122.41570120460688)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[3.4]/[1.3]/float64", Value: Float64ToBinary(37.80832725267146)},
-			{KeyURI: "objectID/features/[1.2]/geometry/coordinates/[0.1]/[3.4]/[2.3]/float64", Value: Float64ToBinary(0)},
-			{KeyURI: "objectID/features/[1.2]/geometry/type/string", Value: []byte("Polygon")},
+			{KeyURI: "objectID/features/[0]/type/string", Value: []byte("Feature")},
+			{KeyURI: "objectID/features/[0]/properties/MAPBLKLOT/string", Value: []byte("0001001")},
+			{KeyURI: "objectID/features/[0]/properties/BLKLOT/string", Value: []byte("0001001")},
+			{KeyURI: "objectID/features/[0]/properties/FROM_ST/string", Value: []byte("0")},
+			{KeyURI: "objectID/features/[0]/properties/STREET/string", Value: []byte("UNKNOWN")},
+			{KeyURI: "objectID/features/[0]/properties/ODD_EVEN/string", Value: []byte("E")},
+			{KeyURI: "objectID/features/[0]/properties/BLOCK_NUM/string", Value: []byte("0001")},
+			{KeyURI: "objectID/features/[0]/properties/LOT_NUM/string", Value: []byte("001")},
+			{KeyURI: "objectID/features/[0]/properties/TO_ST/string", Value: []byte("0")},
+			{KeyURI: "objectID/features/[0]/properties/ST_TYPE/nil", Value: nil},
+			{KeyURI: "objectID/features/[0]/geometry/type/string", Value: []byte("Polygon")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[0]/[0]/float64", Value: Float64ToBinary(-122.42200352825247)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[0]/[1]/float64", Value: Float64ToBinary(37.80848009696725)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[0]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[1]/[0]/float64", Value: Float64ToBinary(-122.42207601332528)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[1]/[1]/float64", Value: Float64ToBinary(37.808835019815085)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[1]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[2]/[0]/float64", Value: Float64ToBinary(-122.42110217434863)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[2]/[1]/float64", Value: Float64ToBinary(37.808803534992904)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[2]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[3]/[0]/float64", Value: Float64ToBinary(-122.42106256906727)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[3]/[1]/float64", Value: Float64ToBinary(37.80860105681815)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[3]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[4]/[0]/float64", Value: Float64ToBinary(-122.42200352825247)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[4]/[1]/float64", Value: Float64ToBinary(37.80848009696725)},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[4]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[1]/type/string", Value: []byte("Feature")},
+			{KeyURI: "objectID/features/[1]/properties/STREET/string", Value: []byte("UNKNOWN")},
+			{KeyURI: "objectID/features/[1]/properties/ODD_EVEN/string", Value: []byte("E")},
+			{KeyURI: "objectID/features/[1]/properties/MAPBLKLOT/string", Value: []byte("0004002")},
+			{KeyURI: "objectID/features/[1]/properties/BLOCK_NUM/string", Value: []byte("0004")},
+			{KeyURI: "objectID/features/[1]/properties/LOT_NUM/string", Value: []byte("002")},
+			{KeyURI: "objectID/features/[1]/properties/FROM_ST/string", Value: []byte("0")},
+			{KeyURI: "objectID/features/[1]/properties/BLKLOT/string", Value: []byte("0004002")},
+			{KeyURI: "objectID/features/[1]/properties/TO_ST/string", Value: []byte("0")},
+			{KeyURI: "objectID/features/[1]/properties/ST_TYPE/nil", Value: nil},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[0]/[0]/float64", Value: Float64ToBinary(-122.41570120460688)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[0]/[1]/float64", Value: Float64ToBinary(37.80832725267146)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[0]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[1]/[0]/float64", Value: Float64ToBinary(-122.4157607435932)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[1]/[1]/float64", Value: Float64ToBinary(37.808630700240904)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[1]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[2]/[0]/float64", Value: Float64ToBinary(-122.4137878913324)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[2]/[1]/float64", Value: Float64ToBinary(37.80856680131984)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[2]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[3]/[0]/float64", Value: Float64ToBinary(-122.41570120460688)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[3]/[1]/float64", Value: Float64ToBinary(37.80832725267146)},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[3]/[2]/float64", Value: Float64ToBinary(0)},
+			{KeyURI: "objectID/features/[1]/geometry/type/string", Value: []byte("Polygon")},
+			{KeyURI: "objectID/features/array", Value: []byte("2")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/array", Value: []byte("1")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/array", Value: []byte("5")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[0]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[1]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[2]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[3]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[0]/geometry/coordinates/[0]/[4]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/array", Value: []byte("1")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/array", Value: []byte("4")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[0]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[1]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[2]/array", Value: []byte("3")},
+			{KeyURI: "objectID/features/[1]/geometry/coordinates/[0]/[3]/array", Value: []byte("3")},
 		},
 	},
 }
@@ -0,0 +1,127 @@
+package doc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/codenotary/merkletree"
+)
+
+// AuditPath represents the nodes required to compute the Merkle root of a
+// document from the hash of one of its properties, without disclosing any of
+// the other properties.
+type AuditPath struct {
+	Position uint64   `json:"position"` // Position of the property, sorted by index.
+	Width    uint64   `json:"width"`    // Number of properties of the document.
+	Path     [][]byte `json:"path"`
+}
+
+// MerkleRoot returns the hex encoded root of the Merkle tree over the content
+// hashes of a property hash list, whose leaves are sorted by index.
+func (p PropertyHashList) MerkleRoot() string {
+	sorted := append(PropertyHashList{}, p...)
+	sort.Sort(sorted)
+
+	var builder MerkleBuilder
+	for _, hash := range sorted {
+		builder.Append(hash.Content)
+	}
+
+	return builder.Root()
+}
+
+// AuditPath returns the audit path of the property of a given key, within the
+// Merkle tree over the content hashes of a property hash list.
+func (p PropertyHashList) AuditPath(key string) (*AuditPath, error) {
+	sorted := append(PropertyHashList{}, p...)
+	sort.Sort(sorted)
+
+	position := -1
+	tree := merkletree.NewMemStore()
+	for i, hash := range sorted {
+		if hash.Key == key {
+			position = i
+		}
+		merkletree.Append(tree, hash.Content)
+	}
+	if position < 0 {
+		return nil, fmt.Errorf("property list does not have key=%s", key)
+	}
+
+	width := uint64(len(sorted))
+	return &AuditPath{
+		Position: uint64(position),
+		Width:    width,
+		Path:     merkletree.InclusionProof(tree, width-1, uint64(position)).ToSlice(),
+	}, nil
+}
+
+// MerkleBuilder computes the root of the Merkle tree over content hashes
+// appended one at a time, in the order of their leaves. Only the roots of the
+// perfect subtrees the leaves appended so far are split into are kept, i.e. one
+// hash per bit set in the number of leaves, so that the root of a document can
+// be computed while its properties are written, whatever their number.
+type MerkleBuilder struct {
+	count    uint64
+	frontier [][sha256.Size]byte // Roots of the perfect subtrees, largest first.
+}
+
+// Append adds the leaf of a content hash to the tree.
+func (b *MerkleBuilder) Append(content []byte) {
+	node := merkletree.LeafHash(content)
+	// Every trailing bit set in the number of leaves is a subtree of the same
+	// size as the one being added, which they are merged with.
+	for n := b.count; n&1 == 1; n >>= 1 {
+		last := len(b.frontier) - 1
+		node = merkleNode(b.frontier[last], node)
+		b.frontier = b.frontier[:last]
+	}
+	b.frontier = append(b.frontier, node)
+	b.count++
+}
+
+// Root returns the hex encoded root of the tree, as defined by RFC 6962.
+func (b *MerkleBuilder) Root() string {
+	if len(b.frontier) == 0 {
+		root := sha256.Sum256(nil)
+		return hex.EncodeToString(root[:])
+	}
+
+	root := b.frontier[len(b.frontier)-1]
+	for i := len(b.frontier) - 2; i >= 0; i-- {
+		root = merkleNode(b.frontier[i], root)
+	}
+
+	return hex.EncodeToString(root[:])
+}
+
+// merkleNode returns the hash of an inner node of a Merkle tree.
+func merkleNode(left, right [sha256.Size]byte) [sha256.Size]byte {
+	node := [sha256.Size*2 + 1]byte{merkletree.NodePrefix}
+	copy(node[1:], left[:])
+	copy(node[sha256.Size+1:], right[:])
+
+	return sha256.Sum256(node[:])
+}
+
+// Verify checks that the audit path leads from the content hash of a property
+// to the given hex encoded Merkle root.
+func (a *AuditPath) Verify(merkleRoot string, contentHash []byte) bool {
+	if a == nil || a.Width == 0 {
+		return false
+	}
+
+	root, err := hex.DecodeString(merkleRoot)
+	if err != nil || len(root) != sha256.Size {
+		return false
+	}
+
+	var path merkletree.Path
+	path.FromSlice(a.Path)
+	var rootHash [sha256.Size]byte
+	copy(rootHash[:], root)
+
+	return path.VerifyInclusion(a.Width-1, a.Position, rootHash, merkletree.LeafHash(contentHash))
+}
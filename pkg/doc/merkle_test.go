@@ -0,0 +1,83 @@
+package doc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/merkletree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyHashListMerkleRoot(t *testing.T) {
+	var hashList PropertyHashList
+	for i := 0; i < 11; i++ {
+		key := fmt.Sprintf("docID/key%02d/string", (i*7)%11)
+		hashList = append(hashList, CreatePropertyHash(uint64(i), []byte(key), []byte("value")))
+	}
+
+	// The leaves are sorted by index, regardless of the order of the list.
+	reversed := make(PropertyHashList, len(hashList))
+	for i, hash := range hashList {
+		reversed[len(hashList)-1-i] = hash
+	}
+	merkleRoot := hashList.MerkleRoot()
+	assert.Equal(t, merkleRoot, reversed.MerkleRoot())
+	assert.NotEqual(t, hashList.Hash(), merkleRoot)
+
+	// Unlike the global hash, the root only depends on the order of the
+	// properties' indexes.
+	var shifted PropertyHashList
+	for _, hash := range hashList {
+		shifted = append(shifted, CreatePropertyHash(hash.Index+100, []byte(hash.Key), []byte("value")))
+	}
+	assert.Equal(t, merkleRoot, shifted.MerkleRoot())
+	assert.NotEqual(t, hashList.Hash(), shifted.Hash())
+
+	for position, hash := range hashList {
+		auditPath, err := hashList.AuditPath(hash.Key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, uint64(11), auditPath.Width)
+		assert.True(t, auditPath.Verify(merkleRoot, hash.Content))
+
+		// The audit path is bound to the property content hash and to the root.
+		assert.False(t, auditPath.Verify(merkleRoot, hash.Hash))
+		assert.False(t, auditPath.Verify(merkleRoot, hashList[(position+1)%11].Content))
+		assert.False(t, auditPath.Verify(hashList.Hash(), hash.Content))
+		assert.False(t, auditPath.Verify("invalid", hash.Content))
+	}
+
+	_, err := hashList.AuditPath("docID/missing/string")
+	assert.EqualError(t, err, "property list does not have key=docID/missing/string")
+
+	single := hashList[:1]
+	auditPath, err := single.AuditPath(single[0].Key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Empty(t, auditPath.Path)
+	assert.True(t, auditPath.Verify(single.MerkleRoot(), single[0].Content))
+
+	empty := sha256.Sum256(nil)
+	assert.Equal(t, hex.EncodeToString(empty[:]), PropertyHashList{}.MerkleRoot())
+	assert.False(t, (&AuditPath{}).Verify(PropertyHashList{}.MerkleRoot(), nil))
+}
+
+func TestMerkleBuilder(t *testing.T) {
+	var builder MerkleBuilder
+	var leaves [][]byte
+	for i := 0; i <= 70; i++ {
+		expected := merkletree.MTH(leaves)
+		assert.Equal(t, hex.EncodeToString(expected[:]), builder.Root(), "%d leaves", i)
+
+		leaf := ContentHash([]byte(fmt.Sprintf("docID/key%d/string", i)), []byte("value"))
+		builder.Append(leaf)
+		leaves = append(leaves, leaf)
+	}
+
+	// The builder keeps one subtree root per bit set in the number of leaves.
+	assert.Len(t, builder.frontier, 4) // 71 = 0b1000111
+}
@@ -0,0 +1,416 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"time"
+	"unicode/utf8"
+)
+
+// MessagePack formats with a special meaning.
+const (
+	msgPackNil      = 0xc0
+	msgPackFalse    = 0xc2
+	msgPackTrue     = 0xc3
+	msgPackFloat32  = 0xca
+	msgPackFloat64  = 0xcb
+	msgPackUint8    = 0xcc
+	msgPackUint16   = 0xcd
+	msgPackUint32   = 0xce
+	msgPackUint64   = 0xcf
+	msgPackInt8     = 0xd0
+	msgPackInt16    = 0xd1
+	msgPackInt32    = 0xd2
+	msgPackInt64    = 0xd3
+	msgPackStr8     = 0xd9
+	msgPackStr16    = 0xda
+	msgPackStr32    = 0xdb
+	msgPackArray16  = 0xdc
+	msgPackArray32  = 0xdd
+	msgPackMap16    = 0xde
+	msgPackMap32    = 0xdf
+	msgPackFixMap   = 0x80
+	msgPackFixArray = 0x90
+	msgPackFixStr   = 0xa0
+)
+
+// msgPackTimestamp is the extension type of timestamps.
+const msgPackTimestamp = -1
+
+// msgPackCodec represents the MessagePack format. Binary values are decoded as
+// their base64 encoding, as done by JSON, and timestamps as RFC 3339 strings,
+// while any other extension type is rejected. Map keys that are not strings
+// are converted to their string representation. Integers beyond 64 bits, which
+// MessagePack cannot represent, are encoded as float64.
+type msgPackCodec struct{}
+
+func (msgPackCodec) Decode(r io.Reader, opts ...Option) (interface{}, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &msgPackDecoder{r: bytes.NewReader(data), o: newOptions(opts)}
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	return value, trailingData(d.r)
+}
+
+func (msgPackCodec) Encode(rawObject interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeMsgPack(&buf, rawObject); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// msgPackDecoder decodes MessagePack values to raw objects.
+type msgPackDecoder struct {
+	r *bytes.Reader
+	o *options
+}
+
+// readN reads the next n bytes.
+func (d *msgPackDecoder) readN(n uint64) ([]byte, error) {
+	if n > uint64(d.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, n)
+	_, _ = d.r.Read(data)
+	return data, nil
+}
+
+// readUint reads a big-endian unsigned integer of the given size in bytes.
+func (d *msgPackDecoder) readUint(size int) (uint64, error) {
+	data, err := d.readN(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, b := range data {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+// decode decodes the next value.
+func (d *msgPackDecoder) decode(depth int) (interface{}, error) {
+	if depth > maxNestingDepth {
+		return nil, fmt.Errorf("maximum nesting depth exceeded")
+	}
+
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	switch {
+	case b <= 0x7f:
+		return intValue(int64(b), d.o), nil
+	case b >= 0xe0:
+		return intValue(int64(int8(b)), d.o), nil
+	case b&0xf0 == msgPackFixMap:
+		return d.decodeMap(uint64(b&0x0f), depth)
+	case b&0xf0 == msgPackFixArray:
+		return d.decodeArray(uint64(b&0x0f), depth)
+	case b&0xe0 == msgPackFixStr:
+		return d.decodeString(uint64(b & 0x1f))
+	}
+
+	switch b {
+	case msgPackNil:
+		return nil, nil
+	case msgPackFalse:
+		return false, nil
+	case msgPackTrue:
+		return true, nil
+	case msgPackFloat32:
+		n, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return floatValue(float64(math.Float32frombits(uint32(n))), d.o)
+	case msgPackFloat64:
+		n, err := d.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return floatValue(math.Float64frombits(n), d.o)
+	case msgPackUint8, msgPackUint16, msgPackUint32, msgPackUint64:
+		n, err := d.readUint(1 << (b - msgPackUint8))
+		if err != nil {
+			return nil, err
+		}
+		return uintValue(n, d.o), nil
+	case msgPackInt8, msgPackInt16, msgPackInt32, msgPackInt64:
+		size := 1 << (b - msgPackInt8)
+		n, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// Sign extension of the integer.
+		shift := uint(64 - 8*size)
+		return intValue(int64(n<<shift)>>shift, d.o), nil
+	case msgPackStr8, msgPackStr16, msgPackStr32:
+		n, err := d.readUint(1 << (b - msgPackStr8))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(n)
+	case 0xc4, 0xc5, 0xc6: // bin 8, 16 and 32.
+		n, err := d.readUint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.readN(n)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	case 0xc7, 0xc8, 0xc9: // ext 8, 16 and 32.
+		n, err := d.readUint(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(n)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8: // fixext 1, 2, 4, 8 and 16.
+		return d.decodeExt(1 << (b - 0xd4))
+	case msgPackArray16, msgPackArray32:
+		n, err := d.readUint(2 << (b - msgPackArray16))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n, depth)
+	case msgPackMap16, msgPackMap32:
+		n, err := d.readUint(2 << (b - msgPackMap16))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n, depth)
+	}
+
+	return nil, fmt.Errorf("invalid MessagePack format 0x%x", b)
+}
+
+// decodeString decodes a string of the given length.
+func (d *msgPackDecoder) decodeString(length uint64) (interface{}, error) {
+	data, err := d.readN(length)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("invalid UTF-8 string")
+	}
+	return string(data), nil
+}
+
+// decodeArray decodes an array of the given length.
+func (d *msgPackDecoder) decodeArray(length uint64, depth int) (interface{}, error) {
+	// Every element takes at least one byte.
+	if length > uint64(d.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	array := make([]interface{}, 0, length)
+	for i := uint64(0); i < length; i++ {
+		element, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, element)
+	}
+	return array, nil
+}
+
+// decodeMap decodes a map of the given length.
+func (d *msgPackDecoder) decodeMap(length uint64, depth int) (interface{}, error) {
+	object := newObjectFor(d.o)
+	members, _ := asObject(object)
+	for i := uint64(0); i < length; i++ {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		keyString, err := mapKey(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		members.Set(keyString, value)
+	}
+	return object, nil
+}
+
+// decodeExt decodes an extension value with data of the given length. Only
+// timestamps are supported.
+func (d *msgPackDecoder) decodeExt(length uint64) (interface{}, error) {
+	extType, err := d.r.ReadByte()
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data, err := d.readN(length)
+	if err != nil {
+		return nil, err
+	}
+	if int8(extType) != msgPackTimestamp {
+		return nil, fmt.Errorf("unsupported MessagePack extension type %d", int8(extType))
+	}
+
+	var seconds int64
+	var nanoseconds uint32
+	switch length {
+	case 4:
+		seconds = int64(binary.BigEndian.Uint32(data))
+	case 8:
+		n := binary.BigEndian.Uint64(data)
+		seconds, nanoseconds = int64(n&0x3ffffffff), uint32(n>>34)
+	case 12:
+		nanoseconds = binary.BigEndian.Uint32(data)
+		seconds = int64(binary.BigEndian.Uint64(data[4:]))
+	default:
+		return nil, fmt.Errorf("invalid MessagePack timestamp")
+	}
+	return time.Unix(seconds, int64(nanoseconds)).UTC().Format(time.RFC3339Nano), nil
+}
+
+// encodeMsgPack encodes a raw object as a MessagePack value.
+func encodeMsgPack(buf *bytes.Buffer, rawObject interface{}) error {
+	if object, ok := asObject(rawObject); ok {
+		writeMsgPackLength(buf, msgPackFixMap, 16, msgPackMap16, uint64(object.Len()))
+		for _, key := range object.Keys() {
+			member, _ := object.Get(key)
+			writeMsgPackString(buf, key)
+			if err := encodeMsgPack(buf, member); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch v := rawObject.(type) {
+	case nil:
+		buf.WriteByte(msgPackNil)
+	case bool:
+		if v {
+			buf.WriteByte(msgPackTrue)
+		} else {
+			buf.WriteByte(msgPackFalse)
+		}
+	case string:
+		writeMsgPackString(buf, v)
+	case float64:
+		writeMsgPackNumber(buf, floatToNative(v))
+	case json.Number:
+		writeMsgPackNumber(buf, numberToNative(v))
+	case []interface{}:
+		writeMsgPackLength(buf, msgPackFixArray, 16, msgPackArray16, uint64(len(v)))
+		for _, element := range v {
+			if err := encodeMsgPack(buf, element); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value of type %T", rawObject)
+	}
+	return nil
+}
+
+// writeMsgPackNumber writes a native number in its most compact form. Integers
+// beyond 64 bits are not supported by the format, hence written as floats.
+func writeMsgPackNumber(buf *bytes.Buffer, number interface{}) {
+	switch n := number.(type) {
+	case int64:
+		switch {
+		case n >= 0:
+			writeMsgPackUint(buf, uint64(n))
+		case n >= -32:
+			buf.WriteByte(byte(n))
+		case n >= math.MinInt8:
+			buf.WriteByte(msgPackInt8)
+			buf.WriteByte(byte(n))
+		case n >= math.MinInt16:
+			buf.WriteByte(msgPackInt16)
+			_ = binary.Write(buf, binary.BigEndian, int16(n))
+		case n >= math.MinInt32:
+			buf.WriteByte(msgPackInt32)
+			_ = binary.Write(buf, binary.BigEndian, int32(n))
+		default:
+			buf.WriteByte(msgPackInt64)
+			_ = binary.Write(buf, binary.BigEndian, n)
+		}
+	case uint64:
+		writeMsgPackUint(buf, n)
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(n).Float64()
+		writeMsgPackNumber(buf, f)
+	case float64:
+		buf.WriteByte(msgPackFloat64)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(n))
+	}
+}
+
+// writeMsgPackUint writes an unsigned integer in its most compact form.
+func writeMsgPackUint(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n <= 0x7f:
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(msgPackUint8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(msgPackUint16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(msgPackUint32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	default:
+		buf.WriteByte(msgPackUint64)
+		_ = binary.Write(buf, binary.BigEndian, n)
+	}
+}
+
+// writeMsgPackString writes a string in its most compact form.
+func writeMsgPackString(buf *bytes.Buffer, s string) {
+	switch n := len(s); {
+	case n < 32:
+		buf.WriteByte(msgPackFixStr | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(msgPackStr8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(msgPackStr16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(msgPackStr32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.WriteString(s)
+}
+
+// writeMsgPackLength writes the length of an array or map, given the fix
+// format and its limit, and the format of 16 bits lengths, the one of 32 bits
+// lengths being the next one.
+func writeMsgPackLength(buf *bytes.Buffer, fixFormat byte, fixLimit uint64, format16 byte, n uint64) {
+	switch {
+	case n < fixLimit:
+		buf.WriteByte(fixFormat | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(format16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(format16 + 1)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
@@ -0,0 +1,157 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgPackCodec_Decode(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{"00", `0`},
+		{"7f", `127`},
+		{"ff", `-1`},
+		{"e0", `-32`},
+		{"cc80", `128`},
+		{"cd0100", `256`},
+		{"ceffffffff", `4294967295`},
+		{"cfffffffffffffffff", `18446744073709551615`},
+		{"d080", `-128`},
+		{"d1ff7f", `-129`},
+		{"d2ffff7fff", `-32769`},
+		{"d38000000000000000", `-9223372036854775808`},
+		{"ca3fc00000", `1.5`},
+		{"cb3ff8000000000000", `1.5`},
+		{"c0", `null`},
+		{"c2", `false`},
+		{"c3", `true`},
+		{"a0", `""`},
+		{"a449455446", `"IETF"`},
+		{"d90449455446", `"IETF"`},
+		{"c40401020304", `"AQIDBA=="`},
+		{"d6ff514b67b0", `"2013-03-21T20:04:00Z"`},
+		{"90", `[]`},
+		{"9301920203920405", `[1,[2,3],[4,5]]`},
+		{"dc0002c2c3", `[false,true]`},
+		{"80", `{}`},
+		{"82a16101a162920203", `{"a":1,"b":[2,3]}`},
+		{"de00010102", `{"1":2}`},
+	}
+
+	for _, tc := range testCases {
+		data, _ := hex.DecodeString(tc.data)
+		value, err := MsgPackCodec.Decode(bytes.NewReader(data), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.data, err)
+		}
+		got, err := json.Marshal(value)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, tc.want, string(got), tc.data)
+	}
+}
+
+func TestMsgPackCodec_DecodeErrors(t *testing.T) {
+	testCases := []struct {
+		data    string
+		wantErr string
+	}{
+		{"", "unexpected EOF"},
+		{"cd01", "unexpected EOF"},
+		{"930102", "unexpected EOF"},
+		{"c1", "invalid MessagePack format 0xc1"},
+		{"d40100", "unsupported MessagePack extension type 1"},
+		{"cb7ff0000000000000", "unsupported number +Inf"},
+		{"a1ff", "invalid UTF-8 string"},
+		{"8190c0", "unsupported map key of type []interface {}"},
+		{"c0c0", "invalid data after top-level value"},
+	}
+
+	for _, tc := range testCases {
+		data, _ := hex.DecodeString(tc.data)
+		_, err := MsgPackCodec.Decode(bytes.NewReader(data))
+		assert.EqualError(t, err, tc.wantErr, tc.data)
+	}
+}
+
+func TestMsgPackCodec_Encode(t *testing.T) {
+	testCases := []struct {
+		json string
+		want string
+	}{
+		{`0`, "00"},
+		{`127`, "7f"},
+		{`128`, "cc80"},
+		{`-1`, "ff"},
+		{`-32`, "e0"},
+		{`-33`, "d0df"},
+		{`-129`, "d1ff7f"},
+		{`256`, "cd0100"},
+		{`65535`, "cdffff"},
+		{`65536`, "ce00010000"},
+		{`4294967296`, "cf0000000100000000"},
+		{`-32769`, "d2ffff7fff"},
+		{`-2147483649`, "d3ffffffff7fffffff"},
+		{`18446744073709551615`, "cfffffffffffffffff"},
+		{`1.5`, "cb3ff8000000000000"},
+		{`false`, "c2"},
+		{`true`, "c3"},
+		{`""`, "a0"},
+		{`"\u00fc"`, "a2c3bc"},
+		{`[]`, "90"},
+		{`{}`, "80"},
+		{`"IETF"`, "a449455446"},
+		{`null`, "c0"},
+		{`[1,[2,3],[4,5]]`, "9301920203920405"},
+		{`{"b":[2,3],"a":1}`, "82a16101a162920203"},
+	}
+
+	for _, tc := range testCases {
+		value, err := JSONCodec.Decode(strings.NewReader(tc.json), WithExactNumbers())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got, err := MsgPackCodec.Encode(value)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.json, err)
+		}
+		assert.Equal(t, tc.want, hex.EncodeToString(got), tc.json)
+	}
+
+	// Strings and containers switch to longer formats with their length.
+	got, err := MsgPackCodec.Encode(strings.Repeat("a", 32))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "d920", hex.EncodeToString(got[:2]))
+
+	got, err = MsgPackCodec.Encode(make([]interface{}, 16))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "dc0010", hex.EncodeToString(got[:3]))
+
+	got, err = MsgPackCodec.Encode(strings.Repeat("a", 256))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "da0100", hex.EncodeToString(got[:3]))
+
+	object := map[string]interface{}{}
+	for i := 0; i < 16; i++ {
+		object[string(rune('a'+i))] = nil
+	}
+	got, err = MsgPackCodec.Encode(object)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "de0010", hex.EncodeToString(got[:3]))
+}
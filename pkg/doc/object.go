@@ -0,0 +1,200 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Object represents a raw JSON object that preserves the order of its members,
+// as decoded in order-preserving mode. Its JSON encoding lists the members in
+// that order.
+type Object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewObject returns an empty Object.
+func NewObject() *Object {
+	return &Object{values: map[string]interface{}{}}
+}
+
+// Len returns the number of members of the object.
+func (o *Object) Len() int {
+	return len(o.values)
+}
+
+// Get returns the value of a member of the object, and whether it exists.
+func (o *Object) Get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set sets the value of a member of the object. New members are added last,
+// while existing ones keep their position.
+func (o *Object) Set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete removes a member of the object.
+func (o *Object) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+
+	keys := o.keys[:0]
+	for _, k := range o.keys {
+		if k != key {
+			keys = append(keys, k)
+		}
+	}
+	o.keys = keys
+}
+
+// Keys returns the keys of the object's members, in order.
+func (o *Object) Keys() []string {
+	keys := make([]string, 0, len(o.values))
+	seen := make(map[string]bool, len(o.values))
+	// The order may be known before every member is set, when rebuilding the
+	// object from its properties, in which case keys are listed twice.
+	for _, key := range o.keys {
+		if _, ok := o.values[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// MarshalJSON encodes the object, listing its members in order.
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.Keys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueData, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyData)
+		buf.WriteByte(':')
+		buf.Write(valueData)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// rawObject abstracts the raw representation of JSON objects, being either a
+// map, or an Object in order-preserving mode.
+type rawObject interface {
+	Len() int
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+	Delete(key string)
+	Keys() []string
+}
+
+// mapObject represents a raw JSON object decoded as a map.
+type mapObject map[string]interface{}
+
+func (m mapObject) Len() int {
+	return len(m)
+}
+
+func (m mapObject) Get(key string) (interface{}, bool) {
+	value, ok := m[key]
+	return value, ok
+}
+
+func (m mapObject) Set(key string, value interface{}) {
+	m[key] = value
+}
+
+func (m mapObject) Delete(key string) {
+	delete(m, key)
+}
+
+func (m mapObject) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// asObject returns the given raw value as an object, if it is one.
+func asObject(value interface{}) (rawObject, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return mapObject(v), true
+	case *Object:
+		return v, true
+	}
+	return nil, false
+}
+
+// newObjectLike returns an empty raw object, ordered if the given raw value is
+// an ordered object.
+func newObjectLike(value interface{}) interface{} {
+	if _, ok := value.(*Object); ok {
+		return NewObject()
+	}
+	return map[string]interface{}{}
+}
+
+// decodeOrdered decodes the next JSON value of a decoder, decoding objects as
+// Objects preserving the order of their members.
+func decodeOrdered(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := NewObject()
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(keyToken.(string), value)
+		}
+		// Closing delimiter.
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return object, nil
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		// Closing delimiter.
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return array, nil
+	}
+
+	return token, nil
+}
@@ -0,0 +1,92 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawToPropertyList_OrderedKeys(t *testing.T) {
+	jsonPayload := []byte(`{"name": "John", "address": {"zip": "10001", "city": "New York"}, "empty": {}, "age": 30}`)
+
+	propertyList, err := RawToPropertyList("docID", bytes.NewReader(jsonPayload), WithOrderedKeys())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.ElementsMatch(t, PropertyEntryList{
+		{KeyURI: "docID/object", Value: []byte(`["name","address","empty","age"]`)},
+		{KeyURI: "docID/name/string", Value: []byte("John")},
+		{KeyURI: "docID/address/object", Value: []byte(`["zip","city"]`)},
+		{KeyURI: "docID/address/zip/string", Value: []byte("10001")},
+		{KeyURI: "docID/address/city/string", Value: []byte("New York")},
+		{KeyURI: "docID/empty/object", Value: []byte(`[]`)},
+		{KeyURI: "docID/age/float64", Value: Float64ToBinary(30)},
+	}, propertyList)
+
+	// The original order is restored, whatever the order of the properties.
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"name":"John","address":{"zip":"10001","city":"New York"},"empty":{},"age":30}`, string(gotPayload))
+
+	// Objects stored without order are sorted.
+	propertyList, err = RawToPropertyList("docID", bytes.NewReader(jsonPayload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotPayload, err = json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"address":{"city":"New York","zip":"10001"},"age":30,"empty":{},"name":"John"}`, string(gotPayload))
+}
+
+func TestApplyPatch_OrderedKeys(t *testing.T) {
+	rawObject, err := DecodeValue([]byte(`{"b": 1, "a": {"d": 2, "c": 3}}`), WithOrderedKeys())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	patch := `[
+		{"op": "add", "path": "/0", "value": {"z": 1, "y": 2}},
+		{"op": "replace", "path": "/b", "value": 4},
+		{"op": "remove", "path": "/a/d"},
+		{"op": "add", "path": "/a/d", "value": 5},
+		{"op": "test", "path": "/0", "value": {"y": 2, "z": 1}}
+	]`
+	patched, err := ApplyPatch(rawObject, strings.NewReader(patch), WithOrderedKeys())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Replaced members keep their position, while added ones come last.
+	gotPayload, err := json.Marshal(patched)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"b":4,"a":{"c":3,"d":5},"0":{"z":1,"y":2}}`, string(gotPayload))
+
+	merged, err := ApplyMergePatch(patched, strings.NewReader(`{"x": {"k": 1, "j": 2}, "b": null, "a": {"c": 6}}`), WithOrderedKeys())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotPayload, err = json.Marshal(merged)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"a":{"c":6,"d":5},"0":{"z":1,"y":2},"x":{"k":1,"j":2}}`, string(gotPayload))
+
+	updated, err := SetValue(merged, "/n/m", "v")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	gotPayload, err = json.Marshal(PropertyListToRaw(ObjectToPropertyList("docID", updated)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"a":{"c":6,"d":5},"0":{"z":1,"y":2},"x":{"k":1,"j":2},"n":{"m":"v"}}`, string(gotPayload))
+}
@@ -0,0 +1,82 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Option configures how JSON payloads are decoded into raw document objects.
+type Option func(*options)
+
+// options represents the decoding options of JSON payloads.
+type options struct {
+	exactNumbers bool
+	orderedKeys  bool
+}
+
+// WithExactNumbers decodes JSON numbers as json.Number, so that they are stored
+// as their exact decimal text rather than as float64, avoiding any precision
+// loss (e.g. integers above 2^53).
+func WithExactNumbers() Option {
+	return func(o *options) {
+		o.exactNumbers = true
+	}
+}
+
+// WithOrderedKeys decodes JSON objects preserving the order of their members,
+// which is recorded along with the document's properties, so that it can be
+// restored when the document is read.
+func WithOrderedKeys() Option {
+	return func(o *options) {
+		o.orderedKeys = true
+	}
+}
+
+// newOptions returns the decoding options resulting from the given ones.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// newDecoder returns a JSON decoder reading from r, set up with the options.
+func (o *options) newDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if o.exactNumbers {
+		decoder.UseNumber()
+	}
+	return decoder
+}
+
+// decode decodes the next JSON value of a decoder to its raw object.
+func (o *options) decode(decoder *json.Decoder) (interface{}, error) {
+	if o.orderedKeys {
+		return decodeOrdered(decoder)
+	}
+
+	var rawValue interface{}
+	if err := decoder.Decode(&rawValue); err != nil {
+		return nil, err
+	}
+	return rawValue, nil
+}
+
+// DecodeValue decodes a single JSON value to its raw object, as done when
+// converting a whole payload to a property list.
+func DecodeValue(data []byte, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+	decoder := o.newDecoder(bytes.NewReader(data))
+
+	rawValue, err := o.decode(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid data after top-level value")
+	}
+	return rawValue, nil
+}
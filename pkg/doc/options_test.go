@@ -0,0 +1,81 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawToPropertyList_ExactNumbers(t *testing.T) {
+	jsonPayload := []byte(`{"index": 18446744073709551615, "id": 9007199254740993, "price": 0.1, "list": [1e400, -0]}`)
+
+	propertyList, err := RawToPropertyList("docID", bytes.NewReader(jsonPayload), WithExactNumbers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.ElementsMatch(t, PropertyEntryList{
+		{KeyURI: "docID/index/number", Value: []byte("18446744073709551615")},
+		{KeyURI: "docID/id/number", Value: []byte("9007199254740993")},
+		{KeyURI: "docID/price/number", Value: []byte("0.1")},
+		{KeyURI: "docID/list/[0]/number", Value: []byte("1e400")},
+		{KeyURI: "docID/list/[1]/number", Value: []byte("-0")},
+		{KeyURI: "docID/list/array", Value: []byte("2")},
+	}, propertyList)
+
+	// The original numbers are rebuilt as is.
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"id":9007199254740993,"index":18446744073709551615,"list":[1e400,-0],"price":0.1}`, string(gotPayload))
+
+	// Numbers stored as float64 are still read.
+	propertyList = append(propertyList, PropertyFloat64([]string{"docID", "age"}, 30))
+	gotPayload, err = json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"age":30,"id":9007199254740993,"index":18446744073709551615,"list":[1e400,-0],"price":0.1}`, string(gotPayload))
+}
+
+func TestDecodeValue(t *testing.T) {
+	value, err := DecodeValue([]byte(`{"id": 9007199254740993}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, map[string]interface{}{"id": float64(9007199254740992)}, value)
+
+	value, err = DecodeValue([]byte(`{"id": 9007199254740993}`), WithExactNumbers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, map[string]interface{}{"id": json.Number("9007199254740993")}, value)
+
+	_, err = DecodeValue([]byte(`"a" "b"`))
+	assert.EqualError(t, err, "invalid data after top-level value")
+
+	_, err = DecodeValue([]byte(``))
+	assert.EqualError(t, err, "EOF")
+}
+
+func TestApplyPatch_ExactNumbers(t *testing.T) {
+	document := map[string]interface{}{"id": json.Number("9007199254740993"), "count": float64(1)}
+
+	patch := `[
+		{"op": "test", "path": "/id", "value": 9007199254740993},
+		{"op": "test", "path": "/count", "value": 1.0},
+		{"op": "add", "path": "/total", "value": 18446744073709551615}
+	]`
+	gotObject, err := ApplyPatch(document, bytes.NewReader([]byte(patch)), WithExactNumbers())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, json.Number("18446744073709551615"), gotObject.(map[string]interface{})["total"])
+
+	// Numbers that only match once rounded to float64 are told apart.
+	patch = `[{"op": "test", "path": "/id", "value": 9007199254740992}]`
+	_, err = ApplyPatch(document, bytes.NewReader([]byte(patch)), WithExactNumbers())
+	assert.EqualError(t, err, "patch operation #0 (test /id) failed: value at '/id' does not match")
+}
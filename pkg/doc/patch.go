@@ -0,0 +1,484 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation represents a single operation of a JSON Patch (RFC 6902).
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ApplyPatch applies the JSON Patch read from the given reader to a raw
+// document object, returning the patched object. The patch is applied as a
+// whole: if any operation fails, an error is returned.
+func ApplyPatch(rawObject interface{}, r io.Reader, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+
+	var operations []PatchOperation
+	if err := o.newDecoder(r).Decode(&operations); err != nil {
+		return nil, fmt.Errorf("unable to unmarshall patch: %v", err)
+	}
+
+	// Operate on a copy, so that the original object is untouched on failure.
+	object := deepCopy(rawObject)
+	for i, operation := range operations {
+		var err error
+		if object, err = applyOperation(object, operation, o); err != nil {
+			return nil, fmt.Errorf("patch operation #%d (%s %s) failed: %v", i, operation.Op, operation.Path, err)
+		}
+	}
+
+	return object, nil
+}
+
+// SetValue sets the value referenced by a JSON Pointer (RFC 6901) in a raw
+// document object, returning the resulting object. Existing values are replaced
+// whatever their type, and missing intermediate objects are created. Array
+// elements are replaced, or appended when referenced by the "-" token or by an
+// index equal to the array's length.
+func SetValue(rawObject interface{}, pointer string, value interface{}) (interface{}, error) {
+	path, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return setValue(deepCopy(rawObject), path, value)
+}
+
+// setValue recursively sets the value referenced by a path.
+func setValue(object interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	if node, ok := asObject(object); ok {
+		child, ok := node.Get(path[0])
+		if !ok && len(path) > 1 {
+			// Missing objects are created of the same kind as their parent.
+			child = newObjectLike(object)
+		}
+		child, err := setValue(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node.Set(path[0], child)
+		return object, nil
+	}
+
+	switch node := object.(type) {
+	case nil:
+		child, err := setValue(nil, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{path[0]: child}, nil
+	case []interface{}:
+		index := len(node)
+		if path[0] != "-" {
+			var err error
+			if index, err = arrayIndex(path[0], len(node)+1); err != nil {
+				return nil, err
+			}
+		}
+		if index == len(node) {
+			node = append(node, nil)
+		}
+		child, err := setValue(node[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("key '%s' can not be set on a scalar value", path[0])
+}
+
+// applyOperation applies a single JSON Patch operation to a raw document object.
+func applyOperation(object interface{}, operation PatchOperation, o *options) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add":
+		value, err := operation.value(o)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(object, path, value)
+	case "remove":
+		object, _, err = removeValue(object, path)
+		return object, err
+	case "replace":
+		value, err := operation.value(o)
+		if err != nil {
+			return nil, err
+		}
+		return replaceValue(object, path, value)
+	case "move":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if len(from) < len(path) && reflect.DeepEqual(from, path[:len(from)]) {
+			return nil, fmt.Errorf("path '%s' is a child of '%s'", operation.Path, operation.From)
+		}
+		object, value, err := removeValue(object, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(object, path, value)
+	case "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := getValue(object, from)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(object, path, deepCopy(value))
+	case "test":
+		value, err := operation.value(o)
+		if err != nil {
+			return nil, err
+		}
+		current, err := getValue(object, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equalValues(current, value) {
+			return nil, fmt.Errorf("value at '%s' does not match", operation.Path)
+		}
+		return object, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation '%s'", operation.Op)
+}
+
+// value returns the decoded value of an operation.
+func (p PatchOperation) value(o *options) (interface{}, error) {
+	if len(p.Value) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+
+	value, err := o.decode(o.newDecoder(bytes.NewReader(p.Value)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid value: %v", err)
+	}
+	return value, nil
+}
+
+// equalValues checks if two raw values are equal. Numbers are compared by
+// their numeric value, whether decoded as float64 or as json.Number, and
+// objects regardless of the order of their members.
+func equalValues(a, b interface{}) bool {
+	if va, ok := asObject(a); ok {
+		vb, ok := asObject(b)
+		if !ok || va.Len() != vb.Len() {
+			return false
+		}
+		for _, key := range va.Keys() {
+			value, _ := va.Get(key)
+			other, ok := vb.Get(key)
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch va := a.(type) {
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if !equalValues(va[i], vb[i]) {
+				return false
+			}
+		}
+		return true
+	case float64, json.Number:
+		ra, okA := numberValue(a)
+		rb, okB := numberValue(b)
+		return okA && okB && ra.Cmp(rb) == 0
+	}
+
+	return a == b
+}
+
+// numberValue returns the exact value of a raw number.
+func numberValue(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case float64:
+		return new(big.Rat).SetFloat64(n), true
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	}
+	return nil, false
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference
+// tokens. The empty pointer, referencing the whole document, has no tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer '%s'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// arrayIndex converts a reference token to an index of an array with the given
+// length. The index must be lower than the given maximum.
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index '%s'", token)
+	}
+	if index >= max {
+		return 0, fmt.Errorf("array index '%s' out of bounds", token)
+	}
+	return index, nil
+}
+
+// getValue returns the value referenced by a path.
+func getValue(object interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		if node, ok := asObject(object); ok {
+			value, ok := node.Get(token)
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found", token)
+			}
+			object = value
+			continue
+		}
+
+		switch node := object.(type) {
+		case []interface{}:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			object = node[index]
+		default:
+			return nil, fmt.Errorf("key '%s' not found", token)
+		}
+	}
+	return object, nil
+}
+
+// updateParent applies a function to the container holding the value
+// referenced by a non-empty path, returning the resulting object.
+func updateParent(object interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(object, path[0])
+	}
+
+	if node, ok := asObject(object); ok {
+		child, ok := node.Get(path[0])
+		if !ok {
+			return nil, fmt.Errorf("key '%s' not found", path[0])
+		}
+		child, err := updateParent(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node.Set(path[0], child)
+		return object, nil
+	}
+
+	switch node := object.(type) {
+	case []interface{}:
+		index, err := arrayIndex(path[0], len(node))
+		if err != nil {
+			return nil, err
+		}
+		child, err := updateParent(node[index], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = child
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("key '%s' not found", path[0])
+}
+
+// addValue adds a value at the location referenced by a path.
+func addValue(object interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return updateParent(object, path, func(parent interface{}, token string) (interface{}, error) {
+		if node, ok := asObject(parent); ok {
+			node.Set(token, value)
+			return parent, nil
+		}
+
+		switch node := parent.(type) {
+		case []interface{}:
+			index := len(node)
+			if token != "-" {
+				var err error
+				if index, err = arrayIndex(token, len(node)+1); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[index+1:], node[index:])
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("key '%s' not found", token)
+	})
+}
+
+// replaceValue replaces the existing value referenced by a path, in place.
+func replaceValue(object interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return updateParent(object, path, func(parent interface{}, token string) (interface{}, error) {
+		if node, ok := asObject(parent); ok {
+			if _, ok := node.Get(token); !ok {
+				return nil, fmt.Errorf("key '%s' not found", token)
+			}
+			node.Set(token, value)
+			return parent, nil
+		}
+
+		switch node := parent.(type) {
+		case []interface{}:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node[index] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("key '%s' not found", token)
+	})
+}
+
+// removeValue removes the value referenced by a path, returning the resulting
+// object and the removed value.
+func removeValue(object interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("the whole document can not be removed")
+	}
+
+	var removed interface{}
+	object, err := updateParent(object, path, func(parent interface{}, token string) (interface{}, error) {
+		if node, ok := asObject(parent); ok {
+			value, ok := node.Get(token)
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found", token)
+			}
+			removed = value
+			node.Delete(token)
+			return parent, nil
+		}
+
+		switch node := parent.(type) {
+		case []interface{}:
+			index, err := arrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			removed = node[index]
+			return append(node[:index], node[index+1:]...), nil
+		}
+		return nil, fmt.Errorf("key '%s' not found", token)
+	})
+
+	return object, removed, err
+}
+
+// deepCopy returns a copy of a raw document object.
+func deepCopy(object interface{}) interface{} {
+	switch node := object.(type) {
+	case map[string]interface{}:
+		nodeCopy := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			nodeCopy[key] = deepCopy(value)
+		}
+		return nodeCopy
+	case *Object:
+		nodeCopy := NewObject()
+		for _, key := range node.Keys() {
+			value, _ := node.Get(key)
+			nodeCopy.Set(key, deepCopy(value))
+		}
+		return nodeCopy
+	case []interface{}:
+		nodeCopy := make([]interface{}, len(node))
+		for i, value := range node {
+			nodeCopy[i] = deepCopy(value)
+		}
+		return nodeCopy
+	}
+	return object
+}
+
+// ApplyMergePatch applies the JSON Merge Patch (RFC 7386) read from the given
+// reader to a raw document object, returning the merged object.
+func ApplyMergePatch(rawObject interface{}, r io.Reader, opts ...Option) (interface{}, error) {
+	o := newOptions(opts)
+	patch, err := o.decode(o.newDecoder(r))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshall patch: %v", err)
+	}
+
+	return mergeValue(deepCopy(rawObject), patch), nil
+}
+
+// mergeValue recursively merges a patch into a target value. Object members
+// of the patch are merged into the target, members set to null being removed,
+// while any other patch value replaces the target.
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := asObject(patch)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := asObject(target)
+	if !ok {
+		target = newObjectLike(patch)
+		targetObject, _ = asObject(target)
+	}
+	for _, key := range patchObject.Keys() {
+		value, _ := patchObject.Get(key)
+		if value == nil {
+			targetObject.Delete(key)
+			continue
+		}
+		current, _ := targetObject.Get(key)
+		targetObject.Set(key, mergeValue(current, value))
+	}
+
+	return target
+}
@@ -0,0 +1,310 @@
+package doc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPatch(t *testing.T) {
+	// Cases taken from RFC 6902, Appendix A.
+	tests := map[string]struct {
+		document string
+		patch    string
+		expected string
+	}{
+		"Add object member": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			expected: `{"baz": "qux", "foo": "bar"}`,
+		},
+		"Add array element": {
+			document: `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		"Add to the end of an array": {
+			document: `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			expected: `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+		"Add nested member object": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			expected: `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		"Add null value": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": null}]`,
+			expected: `{"foo": "bar", "baz": null}`,
+		},
+		"Remove object member": {
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			expected: `{"foo": "bar"}`,
+		},
+		"Remove array element": {
+			document: `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			expected: `{"foo": ["bar", "baz"]}`,
+		},
+		"Replace value": {
+			document: `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		"Move value": {
+			document: `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		"Move array element": {
+			document: `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		"Copy value": {
+			document: `{"foo": {"bar": [1, 2]}}`,
+			patch:    `[{"op": "copy", "from": "/foo/bar", "path": "/baz"}, {"op": "add", "path": "/baz/-", "value": 3}]`,
+			expected: `{"foo": {"bar": [1, 2]}, "baz": [1, 2, 3]}`,
+		},
+		"Test value": {
+			document: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch:    `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		"Escaped pointer": {
+			document: `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}, {"op": "remove", "path": "/~1"}]`,
+			expected: `{"~1": 10}`,
+		},
+		"Replace whole document": {
+			document: `{"foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "", "value": {"baz": "qux"}}]`,
+			expected: `{"baz": "qux"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var document interface{}
+			if err := json.Unmarshal([]byte(test.document), &document); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotObject, err := ApplyPatch(document, bytes.NewReader([]byte(test.patch)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotPayload, err := json.Marshal(gotObject)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, test.expected, string(gotPayload))
+		})
+	}
+}
+
+func TestApplyPatch_Errors(t *testing.T) {
+	tests := map[string]struct {
+		patch       string
+		expectedErr string
+	}{
+		"Invalid patch": {
+			patch:       `{"op": "add"}`,
+			expectedErr: "unable to unmarshall patch: json: cannot unmarshal object into Go value of type []doc.PatchOperation",
+		},
+		"Unknown operation": {
+			patch:       `[{"op": "merge", "path": "/foo"}]`,
+			expectedErr: "patch operation #0 (merge /foo) failed: unknown operation 'merge'",
+		},
+		"Invalid pointer": {
+			patch:       `[{"op": "remove", "path": "foo"}]`,
+			expectedErr: "patch operation #0 (remove foo) failed: invalid pointer 'foo'",
+		},
+		"Missing value": {
+			patch:       `[{"op": "add", "path": "/baz"}]`,
+			expectedErr: "patch operation #0 (add /baz) failed: missing value",
+		},
+		"Remove missing member": {
+			patch:       `[{"op": "remove", "path": "/baz"}]`,
+			expectedErr: "patch operation #0 (remove /baz) failed: key 'baz' not found",
+		},
+		"Add to missing parent": {
+			patch:       `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			expectedErr: "patch operation #0 (add /baz/bat) failed: key 'baz' not found",
+		},
+		"Array index out of bounds": {
+			patch:       `[{"op": "add", "path": "/foo/3", "value": "qux"}]`,
+			expectedErr: "patch operation #0 (add /foo/3) failed: array index '3' out of bounds",
+		},
+		"Invalid array index": {
+			patch:       `[{"op": "replace", "path": "/foo/01", "value": "qux"}]`,
+			expectedErr: "patch operation #0 (replace /foo/01) failed: invalid array index '01'",
+		},
+		"Move to own child": {
+			patch:       `[{"op": "move", "from": "/foo", "path": "/foo/0"}]`,
+			expectedErr: "patch operation #0 (move /foo/0) failed: path '/foo/0' is a child of '/foo'",
+		},
+		"Failed test": {
+			patch:       `[{"op": "add", "path": "/baz", "value": 1}, {"op": "test", "path": "/name", "value": "Jane"}]`,
+			expectedErr: "patch operation #1 (test /name) failed: value at '/name' does not match",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document := map[string]interface{}{
+				"name": "John",
+				"foo":  []interface{}{"bar", "baz"},
+			}
+
+			_, err := ApplyPatch(document, bytes.NewReader([]byte(test.patch)))
+			assert.EqualError(t, err, test.expectedErr)
+
+			// The original document is left untouched.
+			assert.Equal(t, map[string]interface{}{
+				"name": "John",
+				"foo":  []interface{}{"bar", "baz"},
+			}, document)
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	// Cases taken from RFC 7386, Appendix A.
+	tests := map[string]struct {
+		document string
+		patch    string
+		expected string
+	}{
+		"Replace member":         {document: `{"a": "b"}`, patch: `{"a": "c"}`, expected: `{"a": "c"}`},
+		"Add member":             {document: `{"a": "b"}`, patch: `{"b": "c"}`, expected: `{"a": "b", "b": "c"}`},
+		"Remove member":          {document: `{"a": "b"}`, patch: `{"a": null}`, expected: `{}`},
+		"Remove one of members":  {document: `{"a": "b", "b": "c"}`, patch: `{"a": null}`, expected: `{"b": "c"}`},
+		"Replace array":          {document: `{"a": ["b"]}`, patch: `{"a": "c"}`, expected: `{"a": "c"}`},
+		"Replace with array":     {document: `{"a": "c"}`, patch: `{"a": ["b"]}`, expected: `{"a": ["b"]}`},
+		"Merge nested object":    {document: `{"a": {"b": "c"}}`, patch: `{"a": {"b": "d", "c": null}}`, expected: `{"a": {"b": "d"}}`},
+		"Replace array elements": {document: `{"a": [{"b": "c"}]}`, patch: `{"a": [1]}`, expected: `{"a": [1]}`},
+		"Replace document":       {document: `["a", "b"]`, patch: `["c", "d"]`, expected: `["c", "d"]`},
+		"Object over array":      {document: `["a", "b"]`, patch: `{"a": "b"}`, expected: `{"a": "b"}`},
+		"Object over scalar":     {document: `{"e": null}`, patch: `{"a": 1}`, expected: `{"e": null, "a": 1}`},
+		"Nested removal on new":  {document: `{}`, patch: `{"a": {"bb": {"ccc": null}}}`, expected: `{"a": {"bb": {}}}`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var document interface{}
+			if err := json.Unmarshal([]byte(test.document), &document); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotObject, err := ApplyMergePatch(document, bytes.NewReader([]byte(test.patch)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotPayload, err := json.Marshal(gotObject)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, test.expected, string(gotPayload))
+		})
+	}
+}
+
+func TestApplyMergePatch_BadReader(t *testing.T) {
+	_, err := ApplyMergePatch(map[string]interface{}{}, bytes.NewReader([]byte("")))
+	assert.EqualError(t, err, "unable to unmarshall patch: EOF")
+}
+
+func TestSetValue(t *testing.T) {
+	tests := map[string]struct {
+		pointer  string
+		value    interface{}
+		expected string
+	}{
+		"Replace member": {
+			pointer:  "/name",
+			value:    "Jane",
+			expected: `{"name": "Jane", "age": null, "tags": ["a", "b"]}`,
+		},
+		"Change member type": {
+			pointer:  "/age",
+			value:    float64(30),
+			expected: `{"name": "John", "age": 30, "tags": ["a", "b"]}`,
+		},
+		"Replace member with object": {
+			pointer:  "/name",
+			value:    map[string]interface{}{"first": "John"},
+			expected: `{"name": {"first": "John"}, "age": null, "tags": ["a", "b"]}`,
+		},
+		"Add nested member": {
+			pointer:  "/address/city",
+			value:    "Lisbon",
+			expected: `{"name": "John", "age": null, "tags": ["a", "b"], "address": {"city": "Lisbon"}}`,
+		},
+		"Add member under null": {
+			pointer:  "/age/years",
+			value:    float64(30),
+			expected: `{"name": "John", "age": {"years": 30}, "tags": ["a", "b"]}`,
+		},
+		"Replace array element": {
+			pointer:  "/tags/1",
+			value:    "c",
+			expected: `{"name": "John", "age": null, "tags": ["a", "c"]}`,
+		},
+		"Append array element": {
+			pointer:  "/tags/-",
+			value:    "c",
+			expected: `{"name": "John", "age": null, "tags": ["a", "b", "c"]}`,
+		},
+		"Append array element by index": {
+			pointer:  "/tags/2",
+			value:    true,
+			expected: `{"name": "John", "age": null, "tags": ["a", "b", true]}`,
+		},
+		"Replace whole document": {
+			pointer:  "",
+			value:    []interface{}{"a"},
+			expected: `["a"]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			document := map[string]interface{}{
+				"name": "John",
+				"age":  nil,
+				"tags": []interface{}{"a", "b"},
+			}
+
+			gotObject, err := SetValue(document, test.pointer, test.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotPayload, err := json.Marshal(gotObject)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.JSONEq(t, test.expected, string(gotPayload))
+		})
+	}
+}
+
+func TestSetValue_Errors(t *testing.T) {
+	document := map[string]interface{}{"name": "John", "tags": []interface{}{"a"}}
+
+	_, err := SetValue(document, "name", "Jane")
+	assert.EqualError(t, err, "invalid pointer 'name'")
+
+	_, err = SetValue(document, "/name/first", "Jane")
+	assert.EqualError(t, err, "key 'first' can not be set on a scalar value")
+
+	_, err = SetValue(document, "/tags/2", "c")
+	assert.EqualError(t, err, "array index '2' out of bounds")
+}
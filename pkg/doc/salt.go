@@ -0,0 +1,68 @@
+package doc
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// SaltSize is the size of the random salt of salted properties, in bytes.
+const SaltSize = 16
+
+// saltedKeyMarker prefixes the type of the property keys whose values are
+// salted, after the marker of escaped keys, if any. As the key is part of the
+// property's digest, a salted property cannot be passed off as an unsalted one,
+// nor the other way around.
+const saltedKeyMarker = "$"
+
+// SaltedKey marks a property key as having a salted value, unless it already
+// is.
+func SaltedKey(keyURI string) string {
+	typeIdx := strings.LastIndex(keyURI, "/") + 1
+	vType := strings.TrimPrefix(keyURI[typeIdx:], escapedKeyMarker)
+	if strings.HasPrefix(vType, saltedKeyMarker) {
+		return keyURI
+	}
+	markerIdx := len(keyURI) - len(vType)
+	return keyURI[:markerIdx] + saltedKeyMarker + keyURI[markerIdx:]
+}
+
+// IsSalted checks if the value of a property is salted.
+func (p PropertyEntry) IsSalted() bool {
+	return IsSaltedKey(p.KeyURI)
+}
+
+// IsSaltedKey checks if a property key is marked as having a salted value.
+func IsSaltedKey(keyURI string) bool {
+	vType := strings.TrimPrefix(keyURI[strings.LastIndex(keyURI, "/")+1:], escapedKeyMarker)
+	return strings.HasPrefix(vType, saltedKeyMarker)
+}
+
+// SaltPropertyList marks the keys of a property list as having salted values.
+// The values themselves are only salted when written, with SaltValue.
+func SaltPropertyList(properties PropertyEntryList) PropertyEntryList {
+	salted := make(PropertyEntryList, len(properties))
+	for i, property := range properties {
+		salted[i] = PropertyEntry{KeyURI: SaltedKey(property.KeyURI), Value: property.Value}
+	}
+	return salted
+}
+
+// SaltValue prefixes a value with a new random salt, as stored for a salted
+// property.
+func SaltValue(value []byte) ([]byte, error) {
+	salted := make([]byte, SaltSize, SaltSize+len(value))
+	if _, err := rand.Read(salted); err != nil {
+		return nil, fmt.Errorf("unable to generate salt: %v", err)
+	}
+	return append(salted, value...), nil
+}
+
+// SplitSalt splits the stored value of a salted property into its salt and its
+// value.
+func SplitSalt(stored []byte) ([]byte, []byte, error) {
+	if len(stored) < SaltSize {
+		return nil, nil, fmt.Errorf("salted value is shorter than its salt")
+	}
+	return stored[:SaltSize], stored[SaltSize:], nil
+}
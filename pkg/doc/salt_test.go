@@ -0,0 +1,73 @@
+package doc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaltedKey(t *testing.T) {
+	tests := map[string]string{
+		"docID/name/string":            "docID/name/$string",
+		"docID/first%20name/%string":   "docID/first%20name/%$string",
+		"docID/string":                 "docID/$string",
+		"docID/tags/[0]/number":        "docID/tags/[0]/$number",
+		"docID/name/$string":           "docID/name/$string",
+		"docID/first%20name/%$string":  "docID/first%20name/%$string",
+		"docID/meta/object":            "docID/meta/$object",
+		"docID/address/city/%$float64": "docID/address/city/%$float64",
+	}
+
+	for keyURI, expSaltedKey := range tests {
+		saltedKey := SaltedKey(keyURI)
+		assert.Equal(t, expSaltedKey, saltedKey)
+		assert.True(t, IsSaltedKey(saltedKey))
+		assert.True(t, hasKeyFormat(saltedKey))
+
+		_, keys, vType := PropertyEntry{KeyURI: keyURI}.DissectKeyURI()
+		_, saltedKeys, saltedType := PropertyEntry{KeyURI: saltedKey}.DissectKeyURI()
+		assert.Equal(t, keys, saltedKeys)
+		assert.Equal(t, vType, saltedType)
+	}
+	assert.False(t, IsSaltedKey("docID/name/string"))
+	assert.False(t, IsSaltedKey("docID/$name/string"))
+}
+
+func TestSaltValue(t *testing.T) {
+	salted1, err := SaltValue([]byte("true"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	salted2, err := SaltValue([]byte("true"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, SaltSize+4, len(salted1))
+	assert.False(t, bytes.Equal(salted1, salted2))
+
+	salt, value, err := SplitSalt(salted1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, salted1[:SaltSize], salt)
+	assert.Equal(t, []byte("true"), value)
+
+	_, _, err = SplitSalt([]byte("true"))
+	assert.EqualError(t, err, "salted value is shorter than its salt")
+}
+
+func TestSaltPropertyList(t *testing.T) {
+	properties, err := RawToPropertyList("docID", bytes.NewReader([]byte(`{"name": "John", "first name": "J", "tags": [], "age": null}`)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	salted := SaltPropertyList(properties)
+	for i, property := range salted {
+		assert.True(t, property.IsSalted())
+		assert.False(t, properties[i].IsSalted())
+		assert.Equal(t, properties[i].Value, property.Value)
+	}
+	assert.Equal(t, PropertyListToRaw(properties), PropertyListToRaw(salted))
+}
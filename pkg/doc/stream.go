@@ -0,0 +1,146 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// StreamPropertyList reads a JSON payload token by token, calling fn with each
+// property entry of the document as soon as it is complete, so that neither
+// the payload nor its property list are ever held in memory as a whole. Only
+// the keys of the members of the objects being read are kept, hence memory
+// grows with the number of members of the largest object. The entries are the
+// ones created by RawToPropertyList, although not in the same order. Since
+// earlier members have already been handed over, objects with duplicate keys
+// are rejected rather than keeping their last member.
+func StreamPropertyList(docID string, r io.Reader, fn func(PropertyEntry) error, opts ...Option) error {
+	o := newOptions(opts)
+	s := &streamer{
+		decoder:     o.newDecoder(r),
+		orderedKeys: o.orderedKeys,
+		fn:          fn,
+	}
+
+	token, err := s.token()
+	if err != nil {
+		return err
+	}
+	return s.value([]string{docID}, false, token)
+}
+
+// streamer flattens the JSON payload read by a decoder.
+type streamer struct {
+	decoder     *json.Decoder
+	orderedKeys bool
+	fn          func(PropertyEntry) error
+}
+
+// token returns the next token of the payload.
+func (s *streamer) token() (json.Token, error) {
+	token, err := s.decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshall payload: %v", err)
+	}
+	return token, nil
+}
+
+// value flattens the value starting with the given token.
+func (s *streamer) value(keys []string, escaped bool, token json.Token) error {
+	switch v := token.(type) {
+	case json.Delim:
+		if v == '{' {
+			return s.object(keys, escaped)
+		}
+		return s.array(keys, escaped)
+	case nil:
+		return s.emit(PropertyNil(keys), escaped)
+	case string:
+		return s.emit(PropertyString(keys, v), escaped)
+	case bool:
+		return s.emit(PropertyBool(keys, v), escaped)
+	case float64:
+		return s.emit(PropertyFloat64(keys, v), escaped)
+	case json.Number:
+		return s.emit(PropertyNumber(keys, v), escaped)
+	}
+	return nil
+}
+
+// object flattens the members of an object, up to its closing delimiter.
+func (s *streamer) object(keys []string, escaped bool) error {
+	seen := map[string]bool{}
+	var memberKeys []string
+
+	for s.decoder.More() {
+		keyToken, err := s.token()
+		if err != nil {
+			return err
+		}
+		key := keyToken.(string)
+		if seen[key] {
+			return fmt.Errorf("unable to unmarshall payload: duplicate key '%s'", key)
+		}
+		seen[key] = true
+		if s.orderedKeys {
+			memberKeys = append(memberKeys, key)
+		}
+
+		token, err := s.token()
+		if err != nil {
+			return err
+		}
+		escapedKey, isEscaped := EscapeKey(key)
+		keys = append(keys, escapedKey)
+		if err := s.value(keys, escaped || isEscaped, token); err != nil {
+			return err
+		}
+		removeLastElement(&keys)
+	}
+	// Closing delimiter.
+	if _, err := s.token(); err != nil {
+		return err
+	}
+
+	switch {
+	case s.orderedKeys:
+		if memberKeys == nil {
+			memberKeys = []string{}
+		}
+		return s.emit(PropertyObject(keys, memberKeys), escaped)
+	case len(seen) == 0:
+		return s.emit(PropertyEmptyObject(keys), escaped)
+	}
+	return nil
+}
+
+// array flattens the elements of an array, up to its closing delimiter.
+func (s *streamer) array(keys []string, escaped bool) error {
+	length := 0
+	for ; s.decoder.More(); length++ {
+		token, err := s.token()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, "["+strconv.Itoa(length)+"]")
+		if err := s.value(keys, escaped, token); err != nil {
+			return err
+		}
+		removeLastElement(&keys)
+	}
+	// Closing delimiter.
+	if _, err := s.token(); err != nil {
+		return err
+	}
+
+	return s.emit(PropertyArray(keys, length), escaped)
+}
+
+// emit hands over a property entry, marking its key if escaped.
+func (s *streamer) emit(entry PropertyEntry, escaped bool) error {
+	if escaped {
+		entry.KeyURI = markEscapedKey(entry.KeyURI)
+	}
+	return s.fn(entry)
+}
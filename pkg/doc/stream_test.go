@@ -0,0 +1,73 @@
+package doc
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamPropertyList(t *testing.T) {
+	payloads := []string{
+		`{"name": "John", "first name": {"a/b": [1, {"[2]": true}]}, "meta": {}, "tags": [], "nil": null}`,
+		`[[1, [2]], [], [{}]]`,
+		`"John"`,
+		`{"index": 18446744073709551615, "list": [0.1, -0]}`,
+	}
+	files, err := filepath.Glob("../../testdata/*.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		payloads = append(payloads, string(data))
+	}
+
+	for _, opts := range [][]Option{nil, {WithExactNumbers()}, {WithOrderedKeys()}} {
+		for _, payload := range payloads {
+			wantList, err := RawToPropertyList("docID", strings.NewReader(payload), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gotList PropertyEntryList
+			err = StreamPropertyList("docID", strings.NewReader(payload), func(entry PropertyEntry) error {
+				gotList = append(gotList, entry)
+				return nil
+			}, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assert.ElementsMatch(t, wantList, gotList)
+		}
+	}
+}
+
+func TestStreamPropertyList_Errors(t *testing.T) {
+	noop := func(PropertyEntry) error { return nil }
+
+	err := StreamPropertyList("docID", strings.NewReader(`{"a": 1, "a": 2}`), noop)
+	assert.EqualError(t, err, "unable to unmarshall payload: duplicate key 'a'")
+
+	err = StreamPropertyList("docID", strings.NewReader(`{"a": [1, 2`), noop)
+	assert.EqualError(t, err, "unable to unmarshall payload: unexpected end of JSON input")
+
+	err = StreamPropertyList("docID", bytes.NewReader(nil), noop)
+	assert.EqualError(t, err, "unable to unmarshall payload: EOF")
+
+	// Errors of the callback stop the flattening.
+	count := 0
+	err = StreamPropertyList("docID", strings.NewReader(`[1, 2, 3]`), func(PropertyEntry) error {
+		count++
+		return errors.New("write failed")
+	})
+	assert.EqualError(t, err, "write failed")
+	assert.Equal(t, 1, count)
+}
@@ -1,6 +1,7 @@
 package doc
 
 import (
+	"encoding/json"
 	"regexp"
 	"sort"
 	"strconv"
@@ -12,130 +13,132 @@ import (
 func PropertyListToRaw(properties PropertyEntryList) interface{} {
 	sort.Sort(properties)
 
+	arrays := map[string]bool{}
+	for _, property := range properties {
+		if keyType(property.KeyURI) == "array" {
+			arrays[property.KeyURI[:strings.LastIndex(property.KeyURI, "/")]] = true
+		}
+	}
+
 	var rawObject interface{}
 
 	for _, property := range properties {
-		_, keys, vType := property.DissectKeyURI()
-		value := property.Value
-
-		if hasArrayFormat(keys[0]) {
-			index, capacity := splitArrayFormat(keys[0])
-			// Arrays case.
-			if rawObject == nil {
-				rawObject = make([]interface{}, capacity)
-			}
-			propertyListToRawArrays(index, rawObject, 0, keys, vType, value)
-		} else {
-			// Map case.
-			if rawObject == nil {
-				rawObject = map[string]interface{}{}
-			}
-			propertyListToRawMap(rawObject, 0, keys, vType, value)
+		if property.IsAttachment() {
+			continue
 		}
+		_, path, vType := property.dissectPathIn(arrays)
+		rawObject = propertyToRaw(rawObject, path, vType, property.Value)
 	}
 
 	return rawObject
 }
 
-// propertyListToRawMap recursively analyzes a PropertyEntry's Key, building
-// the equivalent structure in the raw document object. This cases deals with
-// the case where the current root element in the path being analyzed consists
-// of a Map.
-func propertyListToRawMap(parentObject interface{}, curKeyIndex int, keys []string, valueType string, value []byte) {
+// propertyToRaw recursively analyzes the path of a PropertyEntry, building the
+// equivalent structure in the given node of the raw document object, and
+// setting the property's value at the leaf. It returns the resulting node,
+// since arrays are grown as their elements are found.
+func propertyToRaw(node interface{}, path []pathSegment, valueType string, value []byte) interface{} {
 	// Leaf object
-	if len(keys) == curKeyIndex+1 {
-		switch object := parentObject.(type) {
-		// Leaf object is a map.
-		case map[string]interface{}:
-			switch valueType {
-			case "nil":
-				object[keys[curKeyIndex]] = nil
-			case "string":
-				object[keys[curKeyIndex]] = string(value)
-			case "bool":
-				object[keys[curKeyIndex]] = string(value) == "true"
-			case "float64":
-				object[keys[curKeyIndex]] = BinaryToFloat64(value)
-			}
+	if len(path) == 0 {
+		if valueType == "array" {
+			// The array's elements may have already been set.
+			array, _ := node.([]interface{})
+			return growArray(array, arrayLength(value))
+		}
+		if valueType == "object" && len(value) > 0 {
+			// The object's members may have already been set.
+			return orderObject(node, value)
 		}
+		return propertyValue(valueType, value)
+	}
+
+	segment := path[0]
 
-		// backtrack
-		return
+	// Arrays case.
+	if segment.isArray {
+		array, _ := node.([]interface{})
+		array = growArray(array, segment.capacity)
+		array = growArray(array, segment.index+1)
+		array[segment.index] = propertyToRaw(array[segment.index], path[1:], valueType, value)
+		return array
 	}
 
-	switch object := parentObject.(type) {
-	// Intermediate node object is a map.
-	case map[string]interface{}:
-		if hasArrayFormat(keys[curKeyIndex+1]) {
-			index, capacity := splitArrayFormat(keys[curKeyIndex+1])
-			// Arrays case.
-			if object[keys[curKeyIndex]] == nil {
-				object[keys[curKeyIndex]] = make([]interface{}, capacity)
-			}
-			propertyListToRawArrays(index, object[keys[curKeyIndex]], curKeyIndex+1, keys, valueType, value)
-		} else {
-			if object[keys[curKeyIndex]] == nil {
-				object[keys[curKeyIndex]] = map[string]interface{}{}
-			}
-			propertyListToRawMap(object[keys[curKeyIndex]], curKeyIndex+1, keys, valueType, value)
-		}
+	// Object case.
+	object, ok := asObject(node)
+	if !ok {
+		node = map[string]interface{}{}
+		object, _ = asObject(node)
 	}
+	child, _ := object.Get(segment.key)
+	object.Set(segment.key, propertyToRaw(child, path[1:], valueType, value))
+	return node
 }
 
-// propertyListToRawArrays recursively analyzes a PropertyEntry's Key, building
-// the equivalent structure in the raw document object. This cases deals with
-// the case where the current root element in the path being analyzed consists
-// of an Array.
-func propertyListToRawArrays(curArrayIndex int, parentArray interface{}, curKeyIndex int, keys []string, valueType string, value []byte) {
-	// Leaf object
-	if len(keys) == curKeyIndex+1 {
-		switch object := parentArray.(type) {
-		case []interface{}:
-			switch valueType {
-			case "nil":
-				object[curArrayIndex] = nil
-			case "string":
-				object[curArrayIndex] = string(value)
-			case "bool":
-				object[curArrayIndex] = string(value) == "true"
-			case "float64":
-				object[curArrayIndex] = BinaryToFloat64(value)
-			}
-		}
-		// backtrack
-		return
+// orderObject returns an ordered object given the value of its property, which
+// lists the keys of its members in order, and the members already set.
+func orderObject(node interface{}, value []byte) interface{} {
+	var keys []string
+	if err := json.Unmarshal(value, &keys); err != nil {
+		return propertyValue("object", nil)
 	}
 
-	switch object := parentArray.(type) {
-	// Intermediate node object is a map.
-	case []interface{}:
-		if hasArrayFormat(keys[curKeyIndex+1]) {
-			index, capacity := splitArrayFormat(keys[curKeyIndex+1])
-			// Arrays case.
-			if object[curArrayIndex] == nil {
-				object[curArrayIndex] = make([]interface{}, capacity)
-			}
-			propertyListToRawArrays(index, object[curArrayIndex], curKeyIndex+1, keys, valueType, value)
-		} else {
-			if object[curArrayIndex] == nil {
-				object[curArrayIndex] = map[string]interface{}{}
-			}
-			propertyListToRawMap(object[curArrayIndex], curKeyIndex+1, keys, valueType, value)
+	object := &Object{keys: keys, values: map[string]interface{}{}}
+	if members, ok := asObject(node); ok {
+		for _, key := range members.Keys() {
+			member, _ := members.Get(key)
+			object.Set(key, member)
 		}
 	}
+	return object
 }
 
-var arrayRegExp = regexp.MustCompile(`^\[\d+\.\d+]$`)
+// growArray grows an array, which may be nil, up to the given length.
+func growArray(array []interface{}, length int) []interface{} {
+	if array == nil {
+		array = make([]interface{}, 0, length)
+	}
+	for len(array) < length {
+		array = append(array, nil)
+	}
+	return array
+}
+
+// propertyValue returns the raw value of a property leaf given its type.
+func propertyValue(valueType string, value []byte) interface{} {
+	switch valueType {
+	case "string":
+		return string(value)
+	case "bool":
+		return string(value) == "true"
+	case "float64":
+		return BinaryToFloat64(value)
+	case "number":
+		return json.Number(value)
+	case "object":
+		return map[string]interface{}{}
+	}
+	return nil
+}
+
+// arrayLength returns the length of an array given the value of its property.
+// Empty arrays stored without length have an empty value.
+func arrayLength(value []byte) int {
+	length, _ := strconv.Atoi(string(value))
+	return length
+}
+
+var arrayRegExp = regexp.MustCompile(`^\[\d+(\.\d+)?]$`)
 
 // hasArrayFormat checks if the current node of the path describes and array element.
 func hasArrayFormat(s string) bool {
-	// Checks for Arrays definitions of the format "[%d.%d]" where the first
-	// parameter describes the current index and the second the total capacity.
+	// Checks for Arrays definitions of the format "[%d]" where the parameter
+	// describes the current index, or of the format "[%d.%d]" of earlier
+	// documents, where the second parameter describes the total capacity.
 	return arrayRegExp.MatchString(s)
 }
 
 // splitArrayFormat given a current array element, returns the associates index
-// and total capacity.
+// and total capacity, the latter being zero if not part of the format.
 func splitArrayFormat(s string) (index int, capacity int) {
 	if !hasArrayFormat(s) {
 		panic("not array format")
@@ -144,7 +147,9 @@ func splitArrayFormat(s string) (index int, capacity int) {
 	indexCapStr := strings.Trim(s, "[]")
 	valuesStr := strings.Split(indexCapStr, ".")
 	index, _ = strconv.Atoi(valuesStr[0])
-	capacity, _ = strconv.Atoi(valuesStr[1])
+	if len(valuesStr) > 1 {
+		capacity, _ = strconv.Atoi(valuesStr[1])
+	}
 
 	return index, capacity
 }
@@ -2,6 +2,7 @@ package doc
 
 import (
 	"encoding/json"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -21,3 +22,72 @@ func TestCreateRawFromPropertyList(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateRawFromPropertyList_CapacityFormat(t *testing.T) {
+	// Documents stored with the array capacity in every element key, and
+	// without array length properties.
+	propertyList := PropertyEntryList{
+		{KeyURI: "objectID/tags/[0.3]/string", Value: []byte("tag1")},
+		{KeyURI: "objectID/tags/[1.3]/string", Value: []byte("tag2")},
+		{KeyURI: "objectID/tags/[2.3]/nil", Value: nil},
+		{KeyURI: "objectID/matrix/[0.2]/[0.2]/float64", Value: Float64ToBinary(1)},
+		{KeyURI: "objectID/matrix/[0.2]/[1.2]/float64", Value: Float64ToBinary(2)},
+		{KeyURI: "objectID/matrix/[1.2]/array", Value: nil},
+		{KeyURI: "objectID/people/[0.1]/name/string", Value: []byte("John")},
+	}
+
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.JSONEq(t, `{
+		"tags": ["tag1", "tag2", null],
+		"matrix": [[1, 2], []],
+		"people": [{"name": "John"}]
+	}`, string(gotPayload))
+}
+
+func TestCreateRawFromPropertyList_ArrayFormatKeys(t *testing.T) {
+	// Documents stored before arrays had a length property, and before keys
+	// of the array element format were escaped, with such object keys.
+	propertyList := PropertyEntryList{
+		{KeyURI: "objectID/scores/[3]/float64", Value: Float64ToBinary(7)},
+		{KeyURI: "objectID/scores/[10]/string", Value: []byte("ten")},
+		{KeyURI: "objectID/tags/[0.2]/[1]/string", Value: []byte("tag")},
+		{KeyURI: "objectID/tags/[1.2]/nil", Value: nil},
+		{KeyURI: "objectID/[2]/string", Value: []byte("two")},
+	}
+
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `{
+		"scores": {"[3]": 7, "[10]": "ten"},
+		"tags": [{"[1]": "tag"}, null],
+		"[2]": "two"
+	}`, string(gotPayload))
+}
+
+func TestCreateRawFromPropertyList_ArrayLength(t *testing.T) {
+	// Trailing null elements, and elements set before the array's length.
+	propertyList := PropertyEntryList{
+		{KeyURI: "objectID/[10]/string", Value: []byte("last")},
+		{KeyURI: "objectID/[9]/nil", Value: nil},
+		{KeyURI: "objectID/[2]/[0]/bool", Value: []byte("true")},
+		{KeyURI: "objectID/[2]/array", Value: []byte("1")},
+		{KeyURI: "objectID/array", Value: []byte("11")},
+	}
+	for i := 0; i < 9; i++ {
+		if i != 2 {
+			propertyList = append(propertyList, PropertyFloat64([]string{"objectID", "[" + strconv.Itoa(i) + "]"}, float64(i)))
+		}
+	}
+
+	gotPayload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.JSONEq(t, `[0, 1, [true], 3, 4, 5, 6, 7, 8, null, "last"]`, string(gotPayload))
+}
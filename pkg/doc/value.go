@@ -0,0 +1,571 @@
+package doc
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// ValueToPropertyList creates the property list for given document provided
+// a Go value. Struct fields are mapped to object members named after their
+// "doc" tag, or else their "json" tag or field name, following the conventions
+// of encoding/json ("-" skips a field, and "omitempty" omits empty values).
+// Integers are stored as their exact decimal text, byte slices as their base64
+// encoding, and values implementing encoding.TextMarshaler, as time.Time does,
+// as their text, pointer receivers included if addressable. Cyclic values are
+// rejected. In order-preserving mode, struct fields keep their order.
+func ValueToPropertyList(docID string, v interface{}, opts ...Option) (PropertyEntryList, error) {
+	e := &valueEncoder{o: newOptions(opts), visiting: map[visit]bool{}}
+	rawObject, err := e.valueToRaw(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+	return rawToPropertyList([]string{docID}, false, rawObject), nil
+}
+
+// PropertyListToValue converts a list of PropertyEntry to the Go value pointed
+// to by v, mapping object members to struct fields as done by
+// ValueToPropertyList.
+func PropertyListToValue(properties PropertyEntryList, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("non-nil pointer required, got %T", v)
+	}
+	return rawToValue(PropertyListToRaw(properties), rv.Elem(), "")
+}
+
+// valueEncoder converts Go values to raw objects, keeping track of the
+// pointers, maps and slices being converted, so that cyclic values are
+// rejected rather than recursing forever.
+type valueEncoder struct {
+	o        *options
+	visiting map[visit]bool
+}
+
+// visit identifies a pointer, map or slice being converted. Slices sharing the
+// same underlying array only match if they have the same length too.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter marks a non-nil pointer, map or slice as being converted, failing if
+// it already is, i.e. if it contains itself. The returned function unmarks it.
+func (e *valueEncoder) enter(v reflect.Value) (func(), error) {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if e.visiting[key] {
+		return nil, fmt.Errorf("unsupported cyclic value of type %s", v.Type())
+	}
+	e.visiting[key] = true
+	return func() { delete(e.visiting, key) }, nil
+}
+
+// valueToRaw converts a Go value to its raw object.
+func (e *valueEncoder) valueToRaw(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+
+	// Values implementing encoding.TextMarshaler with a pointer receiver are
+	// only marshalled as text when addressable, as done by encoding/json.
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(v.Uint(), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("unsupported number %v", f)
+		}
+		return f, nil
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return e.valueToRaw(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return e.valueToRaw(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return e.arrayToRaw(v)
+	case reflect.Array:
+		return e.arrayToRaw(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		object := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKeyToString(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			if object[key], err = e.valueToRaw(iter.Value()); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	case reflect.Struct:
+		rawObject := newObjectFor(e.o)
+		object, _ := asObject(rawObject)
+		for _, f := range structFields(v.Type()) {
+			field := v.FieldByIndex(f.index)
+			if f.omitEmpty && isEmptyValue(field) {
+				continue
+			}
+			member, err := e.valueToRaw(field)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(f.name, member)
+		}
+		return rawObject, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// arrayToRaw converts a Go slice or array to its raw array.
+func (e *valueEncoder) arrayToRaw(v reflect.Value) (interface{}, error) {
+	array := make([]interface{}, v.Len())
+	for i := range array {
+		element, err := e.valueToRaw(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		array[i] = element
+	}
+	return array, nil
+}
+
+// mapKeyToString converts a Go map key to an object key.
+func mapKeyToString(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if key.Type().Implements(textMarshalerType) {
+		text, err := key.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", key.Type())
+}
+
+// rawToValue sets a Go value from its raw object. The path of the value, as a
+// JSON Pointer, describes where errors occur.
+func rawToValue(raw interface{}, v reflect.Value, path string) error {
+	if raw == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return rawToValue(raw, v.Elem(), path)
+	}
+
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		text, ok := raw.(string)
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("invalid value at '%s': %v", path, err)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() > 0 {
+			return decodeError(raw, v, path)
+		}
+		v.Set(reflect.ValueOf(plainValue(raw)))
+		return nil
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := rawInt(raw)
+		if !ok || v.OverflowInt(n) {
+			return decodeError(raw, v, path)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := rawUint(raw)
+		if !ok || v.OverflowUint(n) {
+			return decodeError(raw, v, path)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, ok := rawFloat(raw)
+		if !ok || v.OverflowFloat(f) {
+			return decodeError(raw, v, path)
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		v.SetString(s)
+		return nil
+	case reflect.Slice:
+		if s, ok := raw.(string); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+			data, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return fmt.Errorf("invalid value at '%s': %v", path, err)
+			}
+			v.SetBytes(data)
+			return nil
+		}
+		array, ok := raw.([]interface{})
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(array), len(array)))
+		return rawToArray(array, v, path)
+	case reflect.Array:
+		array, ok := raw.([]interface{})
+		if !ok || len(array) != v.Len() {
+			return decodeError(raw, v, path)
+		}
+		return rawToArray(array, v, path)
+	case reflect.Map:
+		object, ok := asObject(raw)
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		v.Set(reflect.MakeMapWithSize(v.Type(), object.Len()))
+		for _, key := range object.Keys() {
+			keyValue := reflect.New(v.Type().Key()).Elem()
+			if err := stringToMapKey(key, keyValue); err != nil {
+				return fmt.Errorf("invalid key at '%s': %v", path, err)
+			}
+			member, _ := object.Get(key)
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := rawToValue(member, elem, path+"/"+escapePointerToken(key)); err != nil {
+				return err
+			}
+			v.SetMapIndex(keyValue, elem)
+		}
+		return nil
+	case reflect.Struct:
+		object, ok := asObject(raw)
+		if !ok {
+			return decodeError(raw, v, path)
+		}
+		for _, f := range structFields(v.Type()) {
+			member, ok := object.Get(f.name)
+			if !ok {
+				continue
+			}
+			if err := rawToValue(member, v.FieldByIndex(f.index), path+"/"+escapePointerToken(f.name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return decodeError(raw, v, path)
+}
+
+// rawToArray sets the elements of a Go slice or array from a raw array of the
+// same length.
+func rawToArray(array []interface{}, v reflect.Value, path string) error {
+	for i, element := range array {
+		if err := rawToValue(element, v.Index(i), path+"/"+strconv.Itoa(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stringToMapKey sets a Go map key from an object key.
+func stringToMapKey(key string, v reflect.Value) error {
+	if v.Kind() == reflect.String {
+		v.SetString(key)
+		return nil
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key))
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return fmt.Errorf("invalid %s key '%s'", v.Type(), key)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return fmt.Errorf("invalid %s key '%s'", v.Type(), key)
+		}
+		v.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %s", v.Type())
+}
+
+// rawInt returns the integer value of a raw number.
+func rawInt(raw interface{}) (int64, bool) {
+	switch n := raw.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+			return i, true
+		}
+	case float64:
+		if n == math.Trunc(n) && n >= math.MinInt64 && n < math.MaxInt64 {
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+// rawUint returns the unsigned integer value of a raw number.
+func rawUint(raw interface{}) (uint64, bool) {
+	switch n := raw.(type) {
+	case json.Number:
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+			return u, true
+		}
+	case float64:
+		if n == math.Trunc(n) && n >= 0 && n < math.MaxUint64 {
+			return uint64(n), true
+		}
+	}
+	return 0, false
+}
+
+// rawFloat returns the float value of a raw number.
+func rawFloat(raw interface{}) (float64, bool) {
+	switch n := raw.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// plainValue converts the ordered objects of a raw object to maps, as expected
+// by Go values of interface type.
+func plainValue(raw interface{}) interface{} {
+	switch v := raw.(type) {
+	case *Object:
+		object := make(map[string]interface{}, v.Len())
+		for _, key := range v.Keys() {
+			member, _ := v.Get(key)
+			object[key] = plainValue(member)
+		}
+		return object
+	case map[string]interface{}:
+		for key, member := range v {
+			v[key] = plainValue(member)
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = plainValue(element)
+		}
+	}
+	return raw
+}
+
+// decodeError returns the error of a raw object not matching a Go value.
+func decodeError(raw interface{}, v reflect.Value, path string) error {
+	return fmt.Errorf("invalid value at '%s': cannot decode %s into %s", path, rawTypeName(raw), v.Type())
+}
+
+// rawTypeName returns the JSON type name of a raw object.
+func rawTypeName(raw interface{}) string {
+	if _, ok := asObject(raw); ok {
+		return "object"
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case float64:
+		return "number " + strconv.FormatFloat(v, 'g', -1, 64)
+	case json.Number:
+		return "number " + v.String()
+	case []interface{}:
+		return "array"
+	}
+	return "null"
+}
+
+// escapePointerToken escapes a reference token of a JSON Pointer (RFC 6901).
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// field represents a struct field mapped to an object member.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the fields of a struct type mapped to object members,
+// in order. The fields of embedded structs without name are promoted, unless
+// shadowed by a field of the same name.
+func structFields(t reflect.Type) []field {
+	var fields []field
+	names := map[string]bool{}
+	var promoted []field
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			// Unexported field.
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("doc")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if idx := strings.Index(tag, ","); idx >= 0 {
+			name, options = tag[:idx], tag[idx+1:]
+		}
+
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			for _, f := range structFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				promoted = append(promoted, f)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		names[name] = true
+		fields = append(fields, field{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: hasTagOption(options, "omitempty"),
+		})
+	}
+
+	for _, f := range promoted {
+		if !names[f.name] {
+			names[f.name] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// hasTagOption checks if the comma separated options of a tag include the given one.
+func hasTagOption(options, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue checks if a value is empty, as defined by encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City string `doc:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type testAudit struct {
+	CreatedAt time.Time  `json:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt"`
+}
+
+type testCustomer struct {
+	testAudit
+	ID       int64              `json:"id"`
+	Name     string             `json:"name"`
+	Balance  uint64             `json:"balance"`
+	Score    float64            `json:"score"`
+	Active   bool               `json:"active"`
+	Avatar   []byte             `json:"avatar"`
+	Tags     []string           `json:"tags"`
+	Address  *testAddress       `json:"address"`
+	Limits   map[string]int32   `json:"limits"`
+	Extra    interface{}        `json:"extra"`
+	Internal string             `json:"-"`
+	Counts   map[int]uint8      `json:"counts,omitempty"`
+	Pair     [2]int             `json:"pair"`
+	Nested   map[string][]int64 `doc:"nested" json:"ignored"`
+	private  string
+}
+
+func TestValueToPropertyList(t *testing.T) {
+	createdAt := time.Date(2020, 8, 1, 10, 30, 0, 123456789, time.UTC)
+	customer := testCustomer{
+		testAudit: testAudit{CreatedAt: createdAt},
+		ID:        math.MaxInt64,
+		Name:      "John",
+		Balance:   math.MaxUint64,
+		Score:     0.1,
+		Active:    true,
+		Avatar:    []byte{0xff, 0x00, 0x01},
+		Tags:      []string{"a", "b"},
+		Address:   &testAddress{City: "New York"},
+		Limits:    map[string]int32{"daily": -5},
+		Extra:     map[string]interface{}{"note": "vip"},
+		Internal:  "secret",
+		Pair:      [2]int{1, 2},
+		Nested:    map[string][]int64{"ids": {9007199254740993}},
+		private:   "private",
+	}
+
+	propertyList, err := ValueToPropertyList("docID", customer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.ElementsMatch(t, PropertyEntryList{
+		{KeyURI: "docID/createdAt/string", Value: []byte("2020-08-01T10:30:00.123456789Z")},
+		{KeyURI: "docID/deletedAt/nil"},
+		{KeyURI: "docID/id/number", Value: []byte("9223372036854775807")},
+		{KeyURI: "docID/name/string", Value: []byte("John")},
+		{KeyURI: "docID/balance/number", Value: []byte("18446744073709551615")},
+		{KeyURI: "docID/score/float64", Value: Float64ToBinary(0.1)},
+		{KeyURI: "docID/active/bool", Value: []byte("true")},
+		{KeyURI: "docID/avatar/string", Value: []byte("/wAB")},
+		{KeyURI: "docID/tags/[0]/string", Value: []byte("a")},
+		{KeyURI: "docID/tags/[1]/string", Value: []byte("b")},
+		{KeyURI: "docID/tags/array", Value: []byte("2")},
+		{KeyURI: "docID/address/city/string", Value: []byte("New York")},
+		{KeyURI: "docID/limits/daily/number", Value: []byte("-5")},
+		{KeyURI: "docID/extra/note/string", Value: []byte("vip")},
+		{KeyURI: "docID/pair/[0]/number", Value: []byte("1")},
+		{KeyURI: "docID/pair/[1]/number", Value: []byte("2")},
+		{KeyURI: "docID/pair/array", Value: []byte("2")},
+		{KeyURI: "docID/nested/ids/[0]/number", Value: []byte("9007199254740993")},
+		{KeyURI: "docID/nested/ids/array", Value: []byte("1")},
+	}, propertyList)
+
+	var got testCustomer
+	if err := PropertyListToValue(propertyList, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	customer.Internal, customer.private = "", ""
+	assert.Equal(t, customer, got)
+
+	// Struct fields keep their order in order-preserving mode.
+	propertyList, err = ValueToPropertyList("docID", testAddress{City: "New York", Zip: "10001"}, WithOrderedKeys())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload, err := json.Marshal(PropertyListToRaw(propertyList))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, `{"city":"New York","zip":"10001"}`, string(payload))
+}
+
+// testCode implements encoding.TextMarshaler with a pointer receiver.
+type testCode struct {
+	Prefix string
+	Number int
+}
+
+func (c *testCode) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s-%d", c.Prefix, c.Number)), nil
+}
+
+func (c *testCode) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%2s-%d", &c.Prefix, &c.Number)
+	return err
+}
+
+type testNode struct {
+	Name string    `json:"name"`
+	Code testCode  `json:"code"`
+	Next *testNode `json:"next,omitempty"`
+}
+
+func TestValueToPropertyList_PointerReceivers(t *testing.T) {
+	node := &testNode{Name: "a", Code: testCode{Prefix: "AB", Number: 1}, Next: &testNode{Name: "b", Code: testCode{Prefix: "CD", Number: 2}}}
+
+	// Fields are addressable through a pointer, hence marshalled as text.
+	propertyList, err := ValueToPropertyList("docID", node)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.ElementsMatch(t, PropertyEntryList{
+		{KeyURI: "docID/name/string", Value: []byte("a")},
+		{KeyURI: "docID/code/string", Value: []byte("AB-1")},
+		{KeyURI: "docID/next/name/string", Value: []byte("b")},
+		{KeyURI: "docID/next/code/string", Value: []byte("CD-2")},
+	}, propertyList)
+
+	var got testNode
+	if err := PropertyListToValue(propertyList, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, *node, got)
+
+	// As with encoding/json, they are not when the value is not addressable.
+	propertyList, err = ValueToPropertyList("docID", testNode{Code: testCode{Prefix: "AB", Number: 1}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Contains(t, propertyList, PropertyEntry{KeyURI: "docID/code/Prefix/string", Value: []byte("AB")})
+}
+
+func TestValueToPropertyList_Cycles(t *testing.T) {
+	node := &testNode{Name: "a"}
+	node.Next = &testNode{Name: "b", Next: node}
+	_, err := ValueToPropertyList("docID", node)
+	assert.EqualError(t, err, "unsupported cyclic value of type *doc.testNode")
+
+	object := map[string]interface{}{}
+	object["self"] = object
+	_, err = ValueToPropertyList("docID", object)
+	assert.EqualError(t, err, "unsupported cyclic value of type map[string]interface {}")
+
+	array := []interface{}{nil}
+	array[0] = array
+	_, err = ValueToPropertyList("docID", array)
+	assert.EqualError(t, err, "unsupported cyclic value of type []interface {}")
+
+	// Values referenced more than once are not cycles.
+	shared := &testAddress{City: "New York"}
+	propertyList, err := ValueToPropertyList("docID", []*testAddress{shared, shared})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, propertyList, 3)
+}
+
+func TestPropertyListToValue_Errors(t *testing.T) {
+	propertyList := PropertyEntryList{
+		PropertyString([]string{"docID", "id"}, "John"),
+	}
+	var customer testCustomer
+	err := PropertyListToValue(propertyList, &customer)
+	assert.EqualError(t, err, "invalid value at '/id': cannot decode string into int64")
+
+	propertyList = PropertyEntryList{
+		PropertyNumber([]string{"docID", "limits", "daily"}, "2147483648"),
+	}
+	err = PropertyListToValue(propertyList, &customer)
+	assert.EqualError(t, err, "invalid value at '/limits/daily': cannot decode number 2147483648 into int32")
+
+	propertyList = PropertyEntryList{
+		PropertyString([]string{"docID", "createdAt"}, "yesterday"),
+	}
+	err = PropertyListToValue(propertyList, &customer)
+	assert.Error(t, err)
+
+	err = PropertyListToValue(propertyList, customer)
+	assert.EqualError(t, err, "non-nil pointer required, got doc.testCustomer")
+
+	// Numbers stored as float64 are read when integral.
+	propertyList = PropertyEntryList{
+		PropertyFloat64([]string{"docID", "id"}, 30),
+	}
+	if err := PropertyListToValue(propertyList, &customer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, int64(30), customer.ID)
+
+	_, err = ValueToPropertyList("docID", map[string]interface{}{"fn": func() {}})
+	assert.EqualError(t, err, "unsupported type func()")
+}
@@ -79,6 +79,38 @@ func (w *WriteWorkerPool) Write(properties doc.PropertyEntryList) (<-chan *doc.P
 	return w.resultChan, w.shutdownChan, w.errChan
 }
 
+// WriteStream performs the write of the property entries received from the
+// given channel, until it is closed or the pool is stopped. It returns the same
+// channels as Write.
+func (w *WriteWorkerPool) WriteStream(properties <-chan doc.PropertyEntry) (<-chan *doc.PropertyHash, <-chan bool, <-chan error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// The forwarding goroutine is waited for on Stop, so that the job channel
+	// is never closed while in use.
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case propEntry, ok := <-properties:
+				if !ok {
+					return
+				}
+				select {
+				case w.jobChan <- &propEntry:
+				case <-w.shutdownChan:
+					return
+				}
+			case <-w.shutdownChan:
+				return
+			}
+		}
+	}()
+
+	return w.resultChan, w.shutdownChan, w.errChan
+}
+
 // Stop triggers the shutdown of all goroutines within the pool.
 func (w *WriteWorkerPool) Stop() {
 	w.mu.Lock()
@@ -112,3 +112,41 @@ func TestWorker(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkerStream(t *testing.T) {
+	index := 0
+	mock := &ImmuClientMock{
+		wg: &sync.Mutex{},
+		safeSetFn: func(ctx context.Context, key []byte, value []byte) (*immuclient.VerifiedIndex, error) {
+			index++
+			return &immuclient.VerifiedIndex{Index: uint64(index)}, nil
+		},
+	}
+
+	workers := NewWriteWorkerPool(3, mock)
+	err := workers.StartWorkers(context.Background())
+	assert.Nil(t, err)
+	// Stopping with a pending stream should not block.
+	defer workers.Stop()
+
+	properties := make(chan doc.PropertyEntry)
+	resultChan, _, errChan := workers.WriteStream(properties)
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			properties <- doc.PropertyString([]string{"prefix", fmt.Sprintf("key%d", i)}, "value")
+		}
+	}()
+
+	keys := map[string]bool{}
+	for len(keys) < 100 {
+		select {
+		case hash := <-resultChan:
+			keys[hash.Key] = true
+		case err := <-errChan:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assert.True(t, keys["prefix/key0/string"])
+	assert.True(t, keys["prefix/key99/string"])
+}